@@ -2,4 +2,14 @@ module github.com/rubrikinc/rubrik-sdk-for-go
 
 go 1.21
 
-require github.com/mitchellh/mapstructure v1.5.0
+require (
+	github.com/mitchellh/mapstructure v1.5.0
+	go.opentelemetry.io/otel v1.28.0
+	go.opentelemetry.io/otel/metric v1.28.0
+	go.opentelemetry.io/otel/trace v1.28.0
+)
+
+require (
+	github.com/go-logr/logr v1.4.2 // indirect
+	github.com/go-logr/stdr v1.2.2 // indirect
+)
@@ -0,0 +1,257 @@
+// Copyright 2018 Rubrik, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License prop
+//  http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rubrikcdm
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"sync"
+)
+
+// CassetteMode determines whether a cassette records the API calls sent to the Rubrik cluster or replays them.
+type CassetteMode int
+
+const (
+	// CassetteRecord sends the API calls to the Rubrik cluster and saves every request and response to the cassette file,
+	// replacing its previous content.
+	CassetteRecord CassetteMode = iota
+	// CassetteReplay answers the API calls with the responses saved in the cassette file without contacting the Rubrik cluster.
+	CassetteReplay
+)
+
+// ErrNoRecordedInteraction is returned in CassetteReplay mode when the cassette does not contain a response for the API call.
+var ErrNoRecordedInteraction = errors.New("No recorded interaction matches the API call")
+
+// cassetteFile is the JSON document saved to the cassette file.
+type cassetteFile struct {
+	Interactions []cassetteInteraction `json:"interactions"`
+}
+
+// cassetteInteraction is a request sent to the Rubrik cluster and the response it returned.
+type cassetteInteraction struct {
+	Request struct {
+		Method string `json:"method"`
+		Path   string `json:"path"`
+		Body   string `json:"body,omitempty"`
+	} `json:"request"`
+	Response struct {
+		StatusCode int         `json:"statusCode"`
+		Headers    http.Header `json:"headers,omitempty"`
+		Body       string      `json:"body,omitempty"`
+	} `json:"response"`
+}
+
+// cassette records or replays the interactions of a Client.
+type cassette struct {
+	path string
+	mode CassetteMode
+
+	mu           sync.Mutex
+	interactions []cassetteInteraction
+	used         []bool
+}
+
+// cassetteTransport is the http.RoundTripper that sends the requests of a Client through its cassette.
+type cassetteTransport struct {
+	cassette  *cassette
+	transport http.RoundTripper
+}
+
+// WithCassette records the API calls sent by the Client to a JSON cassette file or replays them from it, which can be
+// used to capture the traffic of a lab Rubrik cluster once and run regression tests without network access. The
+// Authorization, Cookie and Set-Cookie headers are never saved and secrets such as passwords, secret keys and session
+// tokens are replaced with [REDACTED] in the request and response bodies.
+//
+// In CassetteReplay mode each API call is answered with the first unused interaction that has the same method, path,
+// query and request body, ignoring the node the request was sent to, so that repeated calls (ex. job status polling)
+// return the responses in the order they were recorded. API calls without a matching interaction fail with
+// ErrNoRecordedInteraction.
+func WithCassette(path string, mode CassetteMode) ClientOption {
+	return func(config *clientConfig) error {
+
+		cassette := &cassette{path: path, mode: mode}
+
+		switch mode {
+		case CassetteRecord:
+			// Create the file immediately so an invalid path is reported by NewClient
+			if err := cassette.save(); err != nil {
+				return err
+			}
+		case CassetteReplay:
+			if err := cassette.load(); err != nil {
+				return err
+			}
+		default:
+			return fmt.Errorf("%d is not a valid CassetteMode", mode)
+		}
+
+		config.cassette = cassette
+		return nil
+	}
+}
+
+// load reads the interactions of the cassette file.
+func (c *cassette) load() error {
+
+	content, err := ioutil.ReadFile(c.path)
+	if err != nil {
+		return fmt.Errorf("Unable to read the cassette %s: %w", c.path, err)
+	}
+
+	var file cassetteFile
+	if err := json.Unmarshal(content, &file); err != nil {
+		return fmt.Errorf("Unable to decode the cassette %s: %w", c.path, err)
+	}
+
+	c.interactions = file.Interactions
+	c.used = make([]bool, len(file.Interactions))
+
+	return nil
+}
+
+// save writes every recorded interaction to the cassette file. The caller must hold the lock once the Client is in use.
+func (c *cassette) save() error {
+
+	// Keep the query separators and JSON bodies readable when reviewing the cassette
+	var content bytes.Buffer
+	encoder := json.NewEncoder(&content)
+	encoder.SetEscapeHTML(false)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(cassetteFile{Interactions: append([]cassetteInteraction{}, c.interactions...)}); err != nil {
+		return err
+	}
+
+	if err := ioutil.WriteFile(c.path, content.Bytes(), 0600); err != nil {
+		return fmt.Errorf("Unable to write the cassette %s: %w", c.path, err)
+	}
+
+	return nil
+}
+
+// RoundTrip records or replays the request depending on the mode of the cassette.
+func (t *cassetteTransport) RoundTrip(request *http.Request) (*http.Response, error) {
+
+	var requestBody []byte
+	if request.Body != nil {
+		var err error
+		requestBody, err = ioutil.ReadAll(request.Body)
+		request.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if t.cassette.mode == CassetteReplay {
+		return t.cassette.replay(request, requestBody)
+	}
+
+	// The original request must not be modified by a RoundTripper
+	outgoing := request.Clone(request.Context())
+	outgoing.Body = ioutil.NopCloser(bytes.NewReader(requestBody))
+
+	response, err := t.transport.RoundTrip(outgoing)
+	if err != nil {
+		return nil, err
+	}
+
+	responseBody, err := ioutil.ReadAll(response.Body)
+	response.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	response.Body = ioutil.NopCloser(bytes.NewReader(responseBody))
+
+	if err := t.cassette.record(request, requestBody, response, responseBody); err != nil {
+		return nil, err
+	}
+
+	return response, nil
+}
+
+// record adds the scrubbed interaction to the cassette and saves it.
+func (c *cassette) record(request *http.Request, requestBody []byte, response *http.Response, responseBody []byte) error {
+
+	var interaction cassetteInteraction
+	interaction.Request.Method = request.Method
+	interaction.Request.Path = cassettePath(request)
+	interaction.Request.Body = string(scrubBody(requestBody))
+	interaction.Response.StatusCode = response.StatusCode
+	interaction.Response.Body = string(scrubBody(responseBody))
+
+	interaction.Response.Headers = http.Header{}
+	for name, values := range response.Header {
+		if redactedHeaders[http.CanonicalHeaderKey(name)] == false {
+			interaction.Response.Headers[name] = values
+		}
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.interactions = append(c.interactions, interaction)
+
+	return c.save()
+}
+
+// replay returns the response of the first unused interaction that matches the request.
+func (c *cassette) replay(request *http.Request, requestBody []byte) (*http.Response, error) {
+
+	path := cassettePath(request)
+	body := string(scrubBody(requestBody))
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for i, interaction := range c.interactions {
+		if c.used[i] || interaction.Request.Method != request.Method || interaction.Request.Path != path || interaction.Request.Body != body {
+			continue
+		}
+		c.used[i] = true
+
+		header := interaction.Response.Headers.Clone()
+		if header == nil {
+			header = http.Header{}
+		}
+
+		return &http.Response{
+			Status:        fmt.Sprintf("%d %s", interaction.Response.StatusCode, http.StatusText(interaction.Response.StatusCode)),
+			StatusCode:    interaction.Response.StatusCode,
+			Proto:         "HTTP/1.1",
+			ProtoMajor:    1,
+			ProtoMinor:    1,
+			Header:        header,
+			Body:          ioutil.NopCloser(bytes.NewReader([]byte(interaction.Response.Body))),
+			ContentLength: int64(len(interaction.Response.Body)),
+			Request:       request,
+		}, nil
+	}
+
+	return nil, newError(ErrNoRecordedInteraction, "No recorded interaction in the cassette %s matches %s %s", c.path, request.Method, path)
+}
+
+// cassettePath returns the path and the normalized query of the request. The node the request was sent to is ignored so
+// that a cassette recorded against one node can be replayed with any node IP.
+func cassettePath(request *http.Request) string {
+
+	query := request.URL.Query()
+	if len(query) == 0 {
+		return request.URL.EscapedPath()
+	}
+
+	return request.URL.EscapedPath() + "?" + query.Encode()
+}
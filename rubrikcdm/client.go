@@ -18,19 +18,23 @@ package rubrikcdm
 
 import (
 	"bytes"
+	"context"
 	"crypto/tls"
 	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
 	"io/ioutil"
 	"net"
 	"net/http"
 	"os"
-	"reflect"
 	"sort"
 	"time"
 )
 
+// userAgent is the User-Agent header sent with every request to the Rubrik cluster.
+const userAgent = "Rubrik Go SDK v1.1.0"
+
 // Type and Constants are used for escaping Get requests
 type encoding int
 
@@ -47,6 +51,11 @@ type Credentials struct {
 	Username string
 	Password string
 	APIToken string
+
+	// config is only set on Credentials created through NewClient()
+	config *clientConfig
+	// timezone is set by Connect(), ConnectAPIToken(), ConnectEnv() and NewClient()
+	timezone *timezoneCache
 }
 
 // Connect initializes a new API client based on manually provided Rubrik cluster credentials. When possible,
@@ -57,6 +66,7 @@ func Connect(nodeIP, username, password string, apiToken ...string) *Credentials
 		NodeIP:   nodeIP,
 		Username: username,
 		Password: password,
+		timezone: &timezoneCache{},
 	}
 
 	return client
@@ -69,6 +79,7 @@ func ConnectAPIToken(nodeIP, apiToken string) *Credentials {
 	client := &Credentials{
 		NodeIP:   nodeIP,
 		APIToken: apiToken,
+		timezone: &timezoneCache{},
 	}
 	return client
 }
@@ -114,12 +125,14 @@ func ConnectEnv() (*Credentials, error) {
 			NodeIP:   nodeIP,
 			Username: username,
 			Password: password,
+			timezone: &timezoneCache{},
 		}
 
 	} else {
 		client = &Credentials{
 			NodeIP:   nodeIP,
 			APIToken: apiToken,
+			timezone: &timezoneCache{},
 		}
 
 	}
@@ -129,69 +142,74 @@ func ConnectEnv() (*Credentials, error) {
 }
 
 // Consolidate the base API functions.
-func (c *Credentials) commonAPI(callType, apiVersion, apiEndpoint string, config interface{}, timeout int) (interface{}, error) {
+func (c *Credentials) commonAPI(ctx context.Context, callType, apiVersion, apiEndpoint string, config interface{}, timeout int) (interface{}, error) {
+
+	apiRequest, apiResponse, err := c.apiCall(ctx, callType, apiVersion, apiEndpoint, config, timeout)
+	if err != nil {
+		return nil, err
+	}
+
+	return parseAPIResponse(apiRequest, apiResponse)
+
+}
+
+// apiCall validates the API version and endpoint, sends the API call and returns the raw response.
+func (c *Credentials) apiCall(ctx context.Context, callType, apiVersion, apiEndpoint string, config interface{}, timeout int) (*http.Response, []byte, error) {
 
 	if apiVersionValidation(apiVersion) == false {
-		return nil, errors.New("Enter a valid API version")
+		return nil, nil, errors.New("Enter a valid API version")
 	}
 
 	if endpointValidation(apiEndpoint) == "errorStart" {
-		return nil, errors.New("The API Endpoint should begin with '/' (ex: /cluster/me)")
+		return nil, nil, errors.New("The API Endpoint should begin with '/' (ex: /cluster/me)")
 	} else if endpointValidation(apiEndpoint) == "errorEnd" {
-		return nil, errors.New("The API Endpoint should not end with '/' (ex. /cluster/me)")
-	}
-
-	tr := &http.Transport{
-		TLSClientConfig: &tls.Config{InsecureSkipVerify: true},
-	}
-	client := &http.Client{
-		Transport: tr,
-		Timeout:   time.Second * time.Duration(timeout),
+		return nil, nil, errors.New("The API Endpoint should not end with '/' (ex. /cluster/me)")
 	}
 
 	requestURL := fmt.Sprintf("https://%s/api/%s%s", c.NodeIP, apiVersion, apiEndpoint)
 
-	var request *http.Request
+	method := callType
+	var requestBody []byte
 	switch callType {
 	case "GET":
-		request, _ = http.NewRequest(callType, getEscape(requestURL), nil)
+		requestURL = getEscape(requestURL)
+	case "GET_ENCODED":
+		// The endpoint has already been escaped
+		method = "GET"
 	case "POST":
-		convertedConfig, _ := json.Marshal(config)
-		request, _ = http.NewRequest(callType, requestURL, bytes.NewBuffer(convertedConfig))
+		requestBody, _ = json.Marshal(config)
+	case "PUT":
+		requestBody, _ = json.Marshal(config)
 	case "PATCH":
-		convertedConfig, _ := json.Marshal(config)
-		request, _ = http.NewRequest(callType, requestURL, bytes.NewBuffer(convertedConfig))
-	case "DELETE":
-		request, _ = http.NewRequest(callType, requestURL, nil)
+		requestBody, _ = json.Marshal(config)
 	case "JOB_STATUS":
 		// Overwrite the default requestURL with the job status url and convert to string
 		requestURL = config.(string)
-		request, _ = http.NewRequest("GET", requestURL, nil)
+		method = "GET"
 	}
-	if len(c.Username) != 0 {
-		request.SetBasicAuth(c.Username, c.Password)
-	} else {
 
-		request.Header.Add("Authorization", fmt.Sprintf("Bearer %s", c.APIToken))
+	apiRequest, apiResponse, err := c.tracedRequest(ctx, method, requestURL, requestBody, timeout)
+	if err != nil {
+		return nil, nil, err
 	}
 
-	request.Header.Set("Content-Type", "application/json")
-	request.Header.Set("Accept", "application/json")
-	request.Header.Set("User-Agent", "Rubrik Go SDK v1.1.0")
-	request.Close = true
-
-	apiRequest, err := client.Do(request)
-	if err, ok := err.(net.Error); ok && err.Timeout() {
-		return nil, errors.New("Unable to establish a connection to the Rubrik cluster")
-	} else if err != nil {
-		return nil, err
+	// The object IDs cached by ObjectID() that the Rubrik cluster no longer knows about are looked up again on the next call
+	if cache := c.objectCache(); cache != nil && apiRequest.StatusCode == http.StatusNotFound {
+		cache.evictPath(apiRequest.Request.URL.Path)
 	}
 
-	defer apiRequest.Body.Close()
+	return apiRequest, apiResponse, nil
 
-	body, err := ioutil.ReadAll(apiRequest.Body)
+}
+
+// parseAPIResponse converts the body of the API response and returns an APIError when the Rubrik cluster responded with an error.
+func parseAPIResponse(apiRequest *http.Response, apiResponse []byte) (interface{}, error) {
 
-	apiResponse := []byte(body)
+	apiError := &APIError{
+		StatusCode: apiRequest.StatusCode,
+		Method:     apiRequest.Request.Method,
+		Endpoint:   apiRequest.Request.URL.RequestURI(),
+	}
 
 	var convertedAPIResponse interface{}
 	if err := json.Unmarshal(apiResponse, &convertedAPIResponse); err != nil {
@@ -201,32 +219,168 @@ func (c *Credentials) commonAPI(callType, apiVersion, apiEndpoint string, config
 			convertedAPIResponse = map[string]interface{}{}
 			convertedAPIResponse.(map[string]interface{})["statusCode"] = apiRequest.StatusCode
 		} else if apiRequest.StatusCode != 200 {
-			return nil, fmt.Errorf("%s", apiRequest.Status)
+			return nil, apiError
 		}
 
 	}
 
-	//
-	if reflect.TypeOf(convertedAPIResponse).Kind() == reflect.Slice {
-		return convertedAPIResponse, nil
+	if responseMap, ok := convertedAPIResponse.(map[string]interface{}); ok {
+
+		if _, ok := responseMap["errorType"]; ok {
+			apiError.ErrorType = fmt.Sprint(responseMap["errorType"])
+			apiError.Message = fmt.Sprint(responseMap["message"])
+			return nil, apiError
+		}
+
+		if _, ok := responseMap["message"]; ok {
+			// Add exception for bootstrap
+			if _, ok := responseMap["setupEncryptionAtRest"]; ok {
+				return convertedAPIResponse, nil
+
+			}
+
+			apiError.Message = fmt.Sprint(responseMap["message"])
+			return nil, apiError
+		}
 	}
 
-	if _, ok := convertedAPIResponse.(map[string]interface{})["errorType"]; ok {
-		return nil, fmt.Errorf("%s", convertedAPIResponse.(map[string]interface{})["message"])
+	if apiRequest.StatusCode >= 400 {
+		return nil, apiError
 	}
 
-	if _, ok := convertedAPIResponse.(map[string]interface{})["message"]; ok {
-		// Add exception for bootstrap
-		if _, ok := convertedAPIResponse.(map[string]interface{})["setupEncryptionAtRest"]; ok {
-			return convertedAPIResponse, nil
+	return convertedAPIResponse, nil
+
+}
+
+// sendRequest sends a request to the Rubrik cluster and returns the response along with its body. When the Credentials
+// use session authentication and the session token has expired, a new session is created and the request is sent again.
+// Transient failures are retried according to the RetryPolicy of the Client.
+func (c *Credentials) sendRequest(ctx context.Context, method, requestURL string, requestBody []byte, timeout int) (*http.Response, []byte, error) {
+
+	client := c.httpClient(timeout)
+	retryPolicy := c.retryPolicy()
+	reauthenticated := false
+	failovers := 0
+
+	for attempt := 1; ; attempt++ {
+
+		var body io.Reader
+		if requestBody != nil {
+			body = bytes.NewReader(requestBody)
+		}
+
+		request, err := http.NewRequestWithContext(ctx, method, requestURL, body)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		// Send the request, including job status URLs, to the node selected by the Client
+		nodeIP := c.selectNode(ctx, timeout)
+		if nodeIP != "" {
+			request.URL.Host = nodeIP
+			request.Host = nodeIP
+		}
+
+		request.Header.Set("Content-Type", "application/json")
+		request.Header.Set("Accept", "application/json")
+		request.Header.Set("User-Agent", userAgent)
+		if c.config == nil {
+			request.Close = true
+		}
+
+		var apiRequest *http.Response
+		var apiResponse []byte
+		err = c.authorize(ctx, request, timeout)
+		if err == nil {
+			start := time.Now()
+			apiRequest, err = client.Do(request)
+			if err == nil {
+				apiResponse, err = ioutil.ReadAll(apiRequest.Body)
+				apiRequest.Body.Close()
+			}
+			c.logRequest(ctx, request, requestBody, apiRequest, apiResponse, attempt, time.Since(start), err)
+		}
+
+		if err != nil {
+			var apiErr *APIError
+			if ctx.Err() != nil {
+				// The caller cancelled the request or its deadline passed
+				return nil, nil, ctx.Err()
+			} else if errors.As(err, &apiErr) {
+				// The Rubrik cluster rejected the session request
+				return nil, nil, err
+			} else if errors.Is(err, ErrNoRecordedInteraction) {
+				// Retrying can not produce a response that was not recorded
+				return nil, nil, errors.Unwrap(err)
+			}
+
+			if nodeIP != "" {
+				c.config.nodes.markUnhealthy(nodeIP)
+				// Immediately send the request to the next node when it is safe to do so
+				if failovers < c.config.nodes.size()-1 && (connectionRefused(err) || retryPolicy.idempotent(method)) {
+					failovers++
+					attempt--
+					continue
+				}
+			}
+
+			if wait, retry := retryPolicy.retryError(method, attempt, err); retry {
+				if err := sleepContext(ctx, wait); err != nil {
+					return nil, nil, err
+				}
+				continue
+			}
+
+			if err, ok := err.(net.Error); ok && err.Timeout() {
+				return nil, nil, errors.New("Unable to establish a connection to the Rubrik cluster")
+			}
+			return nil, nil, err
+		}
+
+		if nodeIP != "" {
+			// The node responded, which also returns a node that was only selected because every node was unhealthy
+			c.config.nodes.markHealthy(nodeIP)
+		}
+
+		if apiRequest.StatusCode == http.StatusUnauthorized && reauthenticated == false && c.usesSession() {
+			// The session token expired, create a new session without counting it as an attempt
+			c.config.clearSession()
+			reauthenticated = true
+			attempt--
+			continue
+		}
 
+		if wait, retry := retryPolicy.retryResponse(method, attempt, apiRequest); retry {
+			if err := sleepContext(ctx, wait); err != nil {
+				return nil, nil, err
+			}
+			continue
 		}
 
-		return nil, fmt.Errorf("%s", convertedAPIResponse.(map[string]interface{})["message"])
+		return apiRequest, apiResponse, nil
 	}
+}
 
-	return convertedAPIResponse, nil
+// httpClient returns the http.Client used to send a request with the provided timeout (in seconds). Credentials created
+// through NewClient() share the configured client and its connections, otherwise a new client that does not verify the
+// Rubrik cluster certificate is created.
+func (c *Credentials) httpClient(timeout int) *http.Client {
+
+	if c.config != nil {
+		client := *c.config.httpClient
+		if client.Timeout == 0 {
+			client.Timeout = time.Second * time.Duration(timeout)
+		}
+		return &client
+	}
 
+	tr := &http.Transport{
+		TLSClientConfig: &tls.Config{InsecureSkipVerify: true},
+	}
+	return &http.Client{
+		Transport: tr,
+		Timeout:   time.Second * time.Duration(timeout),
+	}
 }
 
 // apiVersionValidation validates the API Version provided in the Base API functions. Valid versions are v1, v2 and internal.
@@ -341,10 +495,16 @@ func shouldEscape(c byte, mode encoding) bool {
 // The optional timeout value corresponds to the number of seconds to wait to establish a connection to the Rubrik cluster before returning a
 // timeout error. If no value is provided, a default of 15 seconds will be used.
 func (c *Credentials) Get(apiVersion, apiEndpoint string, timeout ...int) (interface{}, error) {
+	return c.GetContext(context.Background(), apiVersion, apiEndpoint, timeout...)
+}
+
+// GetContext sends a GET request to the provided Rubrik API endpoint using the provided context.Context and returns the full API response.
+// The request is aborted as soon as the context is cancelled or its deadline is exceeded.
+func (c *Credentials) GetContext(ctx context.Context, apiVersion, apiEndpoint string, timeout ...int) (interface{}, error) {
 
 	httpTimeout := httpTimeout(timeout)
 
-	apiRequest, err := c.commonAPI("GET", apiVersion, apiEndpoint, nil, httpTimeout)
+	apiRequest, err := c.commonAPI(ctx, "GET", apiVersion, apiEndpoint, nil, httpTimeout)
 	if err != nil {
 		return nil, err
 	}
@@ -353,47 +513,66 @@ func (c *Credentials) Get(apiVersion, apiEndpoint string, timeout ...int) (inter
 
 }
 
-// JobStatus performs a GET operation to monitor the status of a specific Rubrik job want waits for it's completion.
+// JobStatus performs a GET operation to monitor the status of a specific Rubrik job want waits for it's completion. The status is
+// requested according to the PollPolicy of the Client. A job that finishes with any status other than SUCCEEDED returns its last
+// API response along with an error that matches ErrJobFailed.
 func (c *Credentials) JobStatus(jobStatusURL string, timeout ...int) (interface{}, error) {
+	return c.JobStatusContext(context.Background(), jobStatusURL, timeout...)
+}
 
-	httpTimeout := httpTimeout(timeout)
+// JobStatusContext performs a GET operation to monitor the status of a specific Rubrik job and waits for it's completion. Polling stops
+// and the context error is returned as soon as the provided context.Context is cancelled or its deadline is exceeded.
+func (c *Credentials) JobStatusContext(ctx context.Context, jobStatusURL string, timeout ...int) (interface{}, error) {
 
-	// Dummy place holder values to pass validation
-	apiVersion := "v1"
-	apiEndpoint := "/placeholder"
+	job := c.NewJob(jobStatusURL, timeout...)
 
-	for {
-		apiRequest, err := c.commonAPI("JOB_STATUS", apiVersion, apiEndpoint, jobStatusURL, httpTimeout)
-		if err != nil {
-			return nil, err
-		}
-
-		jobStatus := apiRequest.(map[string]interface{})["status"].(string)
-
-		switch jobStatus {
-		case "SUCCEEDED":
-			return apiRequest, nil
-		case "QUEUED":
-			time.Sleep(10 * time.Second)
-		case "RUNNING":
-			time.Sleep(10 * time.Second)
-		case "FINISHING":
-			time.Sleep(10 * time.Second)
-		default:
-			return apiRequest, errors.New("Job failed")
+	if _, err := job.WaitContext(ctx); err != nil {
+		if errors.Is(err, ErrJobFailed) {
+			return job.Response(), err
 		}
+		return nil, err
 	}
 
+	return job.Response(), nil
+
 }
 
 // Post sends a POST request to the provided Rubrik API endpoint and returns the full API response. Supported "apiVersions" are v1, v2, and internal.
 // The optional timeout value corresponds to the number of seconds to wait to establish a connection to the Rubrik cluster before returning a
 // timeout error. If no value is provided, a default of 15 seconds will be used.
 func (c *Credentials) Post(apiVersion, apiEndpoint string, config interface{}, timeout ...int) (interface{}, error) {
+	return c.PostContext(context.Background(), apiVersion, apiEndpoint, config, timeout...)
+}
+
+// PostContext sends a POST request to the provided Rubrik API endpoint using the provided context.Context and returns the full API response.
+// The request is aborted as soon as the context is cancelled or its deadline is exceeded.
+func (c *Credentials) PostContext(ctx context.Context, apiVersion, apiEndpoint string, config interface{}, timeout ...int) (interface{}, error) {
 
 	httpTimeout := httpTimeout(timeout)
 
-	apiRequest, err := c.commonAPI("POST", apiVersion, apiEndpoint, config, httpTimeout)
+	apiRequest, err := c.commonAPI(ctx, "POST", apiVersion, apiEndpoint, config, httpTimeout)
+	if err != nil {
+		return nil, err
+	}
+
+	return apiRequest, nil
+
+}
+
+// Put sends a PUT request to the provided Rubrik API endpoint and returns the full API response. Supported "apiVersions" are v1, v2, and internal.
+// The optional timeout value corresponds to the number of seconds to wait to establish a connection to the Rubrik cluster before returning a
+// timeout error. If no value is provided, a default of 15 seconds will be used.
+func (c *Credentials) Put(apiVersion, apiEndpoint string, config interface{}, timeout ...int) (interface{}, error) {
+	return c.PutContext(context.Background(), apiVersion, apiEndpoint, config, timeout...)
+}
+
+// PutContext sends a PUT request to the provided Rubrik API endpoint using the provided context.Context and returns the full API response.
+// The request is aborted as soon as the context is cancelled or its deadline is exceeded.
+func (c *Credentials) PutContext(ctx context.Context, apiVersion, apiEndpoint string, config interface{}, timeout ...int) (interface{}, error) {
+
+	httpTimeout := httpTimeout(timeout)
+
+	apiRequest, err := c.commonAPI(ctx, "PUT", apiVersion, apiEndpoint, config, httpTimeout)
 	if err != nil {
 		return nil, err
 	}
@@ -406,10 +585,16 @@ func (c *Credentials) Post(apiVersion, apiEndpoint string, config interface{}, t
 // The optional timeout value corresponds to the number of seconds to wait to establish a connection to the Rubrik cluster before returning a
 // timeout error. If no value is provided, a default of 15 seconds will be used.
 func (c *Credentials) Patch(apiVersion, apiEndpoint string, config interface{}, timeout ...int) (interface{}, error) {
+	return c.PatchContext(context.Background(), apiVersion, apiEndpoint, config, timeout...)
+}
+
+// PatchContext sends a PATCH request to the provided Rubrik API endpoint using the provided context.Context and returns the full API response.
+// The request is aborted as soon as the context is cancelled or its deadline is exceeded.
+func (c *Credentials) PatchContext(ctx context.Context, apiVersion, apiEndpoint string, config interface{}, timeout ...int) (interface{}, error) {
 
 	httpTimeout := httpTimeout(timeout)
 
-	apiRequest, err := c.commonAPI("PATCH", apiVersion, apiEndpoint, config, httpTimeout)
+	apiRequest, err := c.commonAPI(ctx, "PATCH", apiVersion, apiEndpoint, config, httpTimeout)
 	if err != nil {
 		return nil, err
 	}
@@ -422,10 +607,16 @@ func (c *Credentials) Patch(apiVersion, apiEndpoint string, config interface{},
 // The optional timeout value corresponds to the number of seconds to wait to establish a connection to the Rubrik cluster before returning a
 // timeout error. If no value is provided, a default of 15 seconds will be used.
 func (c *Credentials) Delete(apiVersion, apiEndpoint string, timeout ...int) (interface{}, error) {
+	return c.DeleteContext(context.Background(), apiVersion, apiEndpoint, timeout...)
+}
+
+// DeleteContext sends a DELETE request to the provided Rubrik API endpoint using the provided context.Context and returns the full API response.
+// The request is aborted as soon as the context is cancelled or its deadline is exceeded.
+func (c *Credentials) DeleteContext(ctx context.Context, apiVersion, apiEndpoint string, timeout ...int) (interface{}, error) {
 
 	httpTimeout := httpTimeout(timeout)
 
-	apiRequest, err := c.commonAPI("DELETE", apiVersion, apiEndpoint, nil, httpTimeout)
+	apiRequest, err := c.commonAPI(ctx, "DELETE", apiVersion, apiEndpoint, nil, httpTimeout)
 	if err != nil {
 		return nil, err
 	}
@@ -433,6 +624,19 @@ func (c *Credentials) Delete(apiVersion, apiEndpoint string, timeout ...int) (in
 	return apiRequest, nil
 }
 
+// sleepContext pauses for the provided duration and returns early with the context error if the context is cancelled first.
+func sleepContext(ctx context.Context, duration time.Duration) error {
+	timer := time.NewTimer(duration)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
 // stringEq converts b to []string, sorts the two []string, and checks for equality
 func stringEq(a []string, b []interface{}) bool {
 
@@ -14,6 +14,7 @@
 package rubrikcdm
 
 import (
+	"context"
 	"fmt"
 	"reflect"
 	"strings"
@@ -110,12 +111,12 @@ type CloudObjectStore struct {
 
 // JobStatus represents the JSON response for DELETE /internal/archive/location/{id}
 type JobStatus struct {
-	ID        string `json:"id"`
-	Status    string `json:"status"`
-	Progress  int    `json:"progress"`
-	StartTime string `json:"startTime"`
-	EndTime   string `json:"endTime"`
-	NodeID    string `json:"nodeId"`
+	ID        string  `json:"id"`
+	Status    string  `json:"status"`
+	Progress  float64 `json:"progress"`
+	StartTime string  `json:"startTime"`
+	EndTime   string  `json:"endTime"`
+	NodeID    string  `json:"nodeId"`
 	Error     struct {
 		Message string `json:"message"`
 	} `json:"error"`
@@ -324,18 +325,25 @@ type CloudOn struct {
 //
 // The function will return one of the following:
 //
-//	No change required. Cloud native source with access key '{awsAccessKey}' is already configured on the Rubrik cluster.
+//	An error matching ErrNoChangeRequired: No change required. Cloud native source with access key '{awsAccessKey}' is already configured on the Rubrik cluster.
 //
 // //
 //
 //	The full API response for POST /internal/aws/account.
 func (c *Credentials) AddAWSNativeAccount(awsAccountName, awsAccessKey, awsSecretKey string, awsRegions []string, regionalBoltNetworkConfigs interface{}, timeout ...int) (interface{}, error) {
+	return c.AddAWSNativeAccountContext(context.Background(), awsAccountName, awsAccessKey, awsSecretKey, awsRegions, regionalBoltNetworkConfigs, timeout...)
+}
+
+// AddAWSNativeAccountContext is the same as AddAWSNativeAccount with the addition of a context.Context that is used to cancel in-flight requests and any polling.
+func (c *Credentials) AddAWSNativeAccountContext(ctx context.Context, awsAccountName, awsAccessKey, awsSecretKey string, awsRegions []string, regionalBoltNetworkConfigs interface{}, timeout ...int) (interface{}, error) {
+
+	ctx, span := c.startSpan(ctx, "AddAWSNativeAccount")
+	defer span.End()
 
 	httpTimeout := httpTimeout(timeout)
 
-	minimumClusterVersion := c.ClusterVersionCheck(4.2, httpTimeout)
-	if minimumClusterVersion != nil {
-		return nil, minimumClusterVersion
+	if err := c.requireCapability(ctx, CapabilityAWSNativeAccount, httpTimeout); err != nil {
+		return nil, err
 	}
 
 	validAWSRegions := map[string]bool{
@@ -365,7 +373,7 @@ func (c *Credentials) AddAWSNativeAccount(awsAccountName, awsAccessKey, awsSecre
 
 	}
 
-	cloudNativeOnCluster, err := c.Get("internal", "/aws/account", httpTimeout)
+	cloudNativeOnCluster, err := c.getAllContext(ctx, "internal", "/aws/account", httpTimeout)
 	if err != nil {
 		return nil, err
 	}
@@ -380,13 +388,13 @@ func (c *Credentials) AddAWSNativeAccount(awsAccountName, awsAccessKey, awsSecre
 		}
 
 		// TODO - Add additional logic checks for the region and bolt configs
-		currentAccessKey, err := c.Get("internal", fmt.Sprintf("/aws/account/%s", currentAWSConfigID), httpTimeout)
+		currentAccessKey, err := c.GetContext(ctx, "internal", fmt.Sprintf("/aws/account/%s", currentAWSConfigID), httpTimeout)
 		if err != nil {
 			return nil, err
 		}
 
 		if currentAccessKey.(interface{}).(map[string]interface{})["accessKey"].(string) == awsAccessKey {
-			return fmt.Sprintf("No change required. Cloud native source with access key '%s' is already configured on the Rubrik cluster.", awsAccessKey), nil
+			return nil, newError(ErrNoChangeRequired, "No change required. Cloud native source with access key '%s' is already configured on the Rubrik cluster.", awsAccessKey)
 		}
 
 		if currentAWSAccountName == awsAccountName {
@@ -403,7 +411,7 @@ func (c *Credentials) AddAWSNativeAccount(awsAccountName, awsAccessKey, awsSecre
 
 	config["regionalBoltNetworkConfigs"] = regionalBoltNetworkConfigs
 
-	apiRequest, err := c.Post("internal", "/aws/account", config, httpTimeout)
+	apiRequest, err := c.PostContext(ctx, "internal", "/aws/account", config, httpTimeout)
 	if err != nil {
 		return nil, err
 	}
@@ -415,7 +423,7 @@ func (c *Credentials) AddAWSNativeAccount(awsAccountName, awsAccessKey, awsSecre
 		return nil, mapErr
 	}
 
-	status, err := c.JobStatus(addAccount.Links[0].Href, httpTimeout)
+	status, err := c.JobStatusContext(ctx, addAccount.Links[0].Href, httpTimeout)
 	if err != nil {
 		return nil, err
 	}
@@ -424,10 +432,11 @@ func (c *Credentials) AddAWSNativeAccount(awsAccountName, awsAccessKey, awsSecre
 
 }
 
-// ExportEC2Instance exports the latest snapshot of the specified EC2 instance.
+// ExportEC2Instance exports the latest snapshot of the specified EC2 instance and returns the Job monitoring the export. When
+// "waitForCompletion" is true, the Job is returned once the export has completed.
 //
-// The dateTime should be in the following format:  "Month:Day:Year Hour:Minute AM/PM". Ex. 04-09-2019 05:56 PM. You may also use "latest" to export the last
-// snapshot taken.
+// The dateTime is interpreted in the time zone of the Rubrik cluster and can use any format accepted by ParseDateTime(), ex. "Month-Day-Year Hour:Minute AM/PM"
+// (04-09-2019 05:56 PM). The snapshot taken during that minute is exported. You may also use "latest" to export the last snapshot taken.
 //
 // Valid "awsRegion" choices are:
 //
@@ -446,13 +455,54 @@ func (c *Credentials) AddAWSNativeAccount(awsAccountName, awsAccessKey, awsSecre
 // x1e.16xlarge, x1e.32xlarge, z1d.large, z1d.xlarge, z1d.2xlarge, z1d.3xlarge, z1d.6xlarge, z1d.12xlarge, d2.xlarge, d2.2xlarge, d2.4xlarge, d2.8xlarge, h1.2xlarge,
 // h1.4xlarge,  h1.8xlarge, h1.16xlarge, i3.large, i3.xlarge, i3.2xlarge, i3.4xlarge, i3.8xlarge, i3.16xlarge, f1.2xlarge, f1.4xlarge, f1.16xlarge, g3s.xlarge, g3.4xlarge,
 // g3.8xlarge, g3.16xlarge, p2.xlarge, p2.8xlarge, p2.16xlarge, p3.2xlarge, p3.8xlarge, p3.16xlarge, and p3dn.24xlarge.
-func (c *Credentials) ExportEC2Instance(instanceID, exportedInstanceName, instanceType, awsRegion, subnetID, securityGroupID, dateTime string, waitForCompletion bool, timeout ...int) (interface{}, error) {
+func (c *Credentials) ExportEC2Instance(instanceID, exportedInstanceName, instanceType, awsRegion, subnetID, securityGroupID, dateTime string, waitForCompletion bool, timeout ...int) (*Job, error) {
+	return c.ExportEC2InstanceContext(context.Background(), instanceID, exportedInstanceName, instanceType, awsRegion, subnetID, securityGroupID, dateTime, waitForCompletion, timeout...)
+}
 
-	httpTimeout := httpTimeout(timeout)
+// ExportEC2InstanceContext is the same as ExportEC2Instance with the addition of a context.Context that is used to cancel in-flight requests and any polling.
+func (c *Credentials) ExportEC2InstanceContext(ctx context.Context, instanceID, exportedInstanceName, instanceType, awsRegion, subnetID, securityGroupID, dateTime string, waitForCompletion bool, timeout ...int) (*Job, error) {
+
+	ctx, span := c.startSpan(ctx, "ExportEC2Instance")
+	defer span.End()
+
+	var snapshotDateTime time.Time
+	if dateTime != "latest" {
+		var err error
+		snapshotDateTime, err = c.ClusterDateTimeContext(ctx, dateTime, timeout...)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return c.exportEC2Instance(ctx, instanceID, exportedInstanceName, instanceType, awsRegion, subnetID, securityGroupID, snapshotDateTime, dateTime, waitForCompletion, httpTimeout(timeout))
+}
+
+// ExportEC2InstanceAt is the same as ExportEC2Instance with the point in time of the snapshot provided as a time.Time. The
+// zero time.Time exports the last snapshot taken.
+func (c *Credentials) ExportEC2InstanceAt(instanceID, exportedInstanceName, instanceType, awsRegion, subnetID, securityGroupID string, dateTime time.Time, waitForCompletion bool, timeout ...int) (*Job, error) {
+	return c.ExportEC2InstanceAtContext(context.Background(), instanceID, exportedInstanceName, instanceType, awsRegion, subnetID, securityGroupID, dateTime, waitForCompletion, timeout...)
+}
+
+// ExportEC2InstanceAtContext is the same as ExportEC2InstanceAt with the addition of a context.Context that is used to cancel in-flight requests and any polling.
+func (c *Credentials) ExportEC2InstanceAtContext(ctx context.Context, instanceID, exportedInstanceName, instanceType, awsRegion, subnetID, securityGroupID string, dateTime time.Time, waitForCompletion bool, timeout ...int) (*Job, error) {
 
-	minimumClusterVersion := c.ClusterVersionCheck(4.2, httpTimeout)
-	if minimumClusterVersion != nil {
-		return nil, minimumClusterVersion
+	ctx, span := c.startSpan(ctx, "ExportEC2InstanceAt")
+	defer span.End()
+
+	label := "latest"
+	if dateTime.IsZero() == false {
+		label = dateTime.Format(time.RFC3339)
+	}
+
+	return c.exportEC2Instance(ctx, instanceID, exportedInstanceName, instanceType, awsRegion, subnetID, securityGroupID, dateTime, label, waitForCompletion, httpTimeout(timeout))
+}
+
+// exportEC2Instance exports the snapshot of the EC2 instance taken during the minute of "dateTime", or the last snapshot
+// when it is the zero time.Time. The "label" is the dateTime as provided by the caller for error messages.
+func (c *Credentials) exportEC2Instance(ctx context.Context, instanceID, exportedInstanceName, instanceType, awsRegion, subnetID, securityGroupID string, dateTime time.Time, label string, waitForCompletion bool, httpTimeout int) (*Job, error) {
+
+	if err := c.requireCapability(ctx, CapabilityEC2InstanceExport, httpTimeout); err != nil {
+		return nil, err
 	}
 
 	validAWSRegions := map[string]bool{
@@ -614,50 +664,37 @@ func (c *Credentials) ExportEC2Instance(instanceID, exportedInstanceName, instan
 	}
 
 	if validAWSRegions[awsRegion] == false {
-		return "", fmt.Errorf("%s is not a valid AWS Region", awsRegion)
+		return nil, fmt.Errorf("%s is not a valid AWS Region", awsRegion)
 	}
 
-	objectID, err := c.ObjectID(instanceID, "ec2", httpTimeout)
+	objectID, err := c.ObjectIDContext(ctx, instanceID, ObjectTypeEC2, httpTimeout)
 	if err != nil {
 		return nil, err
 
 	}
 
-	allSnapshots, err := c.Get("internal", fmt.Sprintf("/aws/ec2_instance/%s/snapshot", objectID), httpTimeout)
+	snapshots, err := c.SnapshotsContext(ctx, ObjectTypeEC2, objectID, httpTimeout)
 	if err != nil {
 		return nil, err
 	}
 
-	// Convert the API Response (map[string]interface{}) to a struct
-	var snapshot ExportEC2Instance
-	mapErr := mapstructure.Decode(allSnapshots, &snapshot)
-	if mapErr != nil {
-		return nil, mapErr
-	}
-
-	var snapshotID string
-	if dateTime == "latest" {
-		snapshotID = snapshot.Data[snapshot.Total-1].ID
-	} else {
-		snapshotTime, err := c.DateTimeConversion(dateTime)
-		if err != nil {
-			return "", err
+	var snapshot Snapshot
+	var found bool
+	if dateTime.IsZero() {
+		snapshot, found = Latest().Select(snapshots)
+		if found == false {
+			return nil, newError(ErrNotFound, "The EC2 Instance '%s' does not have any snapshots", instanceID)
 		}
-
-		for _, snapshot := range snapshot.Data {
-			// The API records EC2 Snapshots down to the second so we have to remove the :00Z from the end to check if the converted dateTime
-			// is present in the returned dates
-			if strings.Contains(snapshot.Date, strings.TrimSuffix(snapshotTime, ":00Z")) {
-				snapshotID = snapshot.ID
-			}
-
-		}
-
-		if snapshotID == "" {
-			return "", fmt.Errorf("The EC2 Instance '%s' does not have a snapshot take on '%s'", instanceID, dateTime)
+	} else {
+		// The API records EC2 Snapshots down to the second while the dateTime is down to the minute
+		minute := dateTime.Truncate(time.Minute)
+		snapshot, found = Latest().Where(TakenBetween(minute, minute.Add(time.Minute))).Select(snapshots)
+		if found == false {
+			return nil, newError(ErrNotFound, "The EC2 Instance '%s' does not have a snapshot take on '%s'", instanceID, label)
 		}
 
 	}
+	snapshotID := snapshot.ID
 
 	config := map[string]string{}
 	config["instanceName"] = exportedInstanceName
@@ -666,61 +703,59 @@ func (c *Credentials) ExportEC2Instance(instanceID, exportedInstanceName, instan
 	config["subnetId"] = subnetID
 	config["securityGroupId"] = securityGroupID
 
-	exportInstance, err := c.Post("internal", fmt.Sprintf("/aws/ec2_instance/snapshot/%s/export", snapshotID), config, httpTimeout)
+	exportInstance, err := c.PostContext(ctx, "internal", fmt.Sprintf("/aws/ec2_instance/snapshot/%s/export", snapshotID), config, httpTimeout)
 	if err != nil {
 		return nil, err
 	}
 
-	// Convert the API Response (map[string]interface{}) to a struct
-	var export JobStatus
-	exportMapErr := mapstructure.Decode(exportInstance, &export)
-	if exportMapErr != nil {
-		return nil, exportMapErr
+	job, err := c.newJob(exportInstance, httpTimeout)
+	if err != nil {
+		return nil, err
 	}
 
 	if waitForCompletion == true {
-
-		status, err := c.JobStatus(export.Links[0].Href, httpTimeout)
-		if err != nil {
+		if _, err := job.WaitContext(ctx); err != nil {
 			return nil, err
 		}
-
-		return status, nil
-
 	}
 
-	return export, nil
+	return job, nil
 
 }
 
-// RemoveAWSAccount deletes the specific AWS account from the Rubrik cluster and waits for the job to complete before returning the JobStatus API response.
-func (c *Credentials) RemoveAWSAccount(awsAccountName string, deleteExistingSnapshots bool, timeout ...int) (interface{}, error) {
+// RemoveAWSAccount deletes the specific AWS account from the Rubrik cluster and waits for the job to complete before returning it.
+func (c *Credentials) RemoveAWSAccount(awsAccountName string, deleteExistingSnapshots bool, timeout ...int) (*Job, error) {
+	return c.RemoveAWSAccountContext(context.Background(), awsAccountName, deleteExistingSnapshots, timeout...)
+}
+
+// RemoveAWSAccountContext is the same as RemoveAWSAccount with the addition of a context.Context that is used to cancel in-flight requests and any polling.
+func (c *Credentials) RemoveAWSAccountContext(ctx context.Context, awsAccountName string, deleteExistingSnapshots bool, timeout ...int) (*Job, error) {
+
+	ctx, span := c.startSpan(ctx, "RemoveAWSAccount")
+	defer span.End()
 
 	httpTimeout := httpTimeout(timeout)
 
-	awsAccountSummary, err := c.AWSAccountSummary(awsAccountName, httpTimeout)
+	awsAccountSummary, err := c.AWSAccountSummaryContext(ctx, awsAccountName, httpTimeout)
 	if err != nil {
 		return nil, err
 	}
 
-	deleteAPIRequest, err := c.Delete("internal", fmt.Sprintf("/aws/account/%s?delete_existing_snapshots=%t", awsAccountSummary.ID, deleteExistingSnapshots), httpTimeout)
+	deleteAPIRequest, err := c.DeleteContext(ctx, "internal", fmt.Sprintf("/aws/account/%s?delete_existing_snapshots=%t", awsAccountSummary.ID, deleteExistingSnapshots), httpTimeout)
 	if err != nil {
 		return nil, err
 	}
 
-	// Convert the API Response (map[string]interface{}) to a struct
-	var deleteAccount JobStatus
-	mapErr := mapstructure.Decode(deleteAPIRequest, &deleteAccount)
-	if mapErr != nil {
-		return nil, mapErr
+	job, err := c.newJob(deleteAPIRequest, httpTimeout)
+	if err != nil {
+		return nil, err
 	}
 
-	status, err := c.JobStatus(deleteAccount.Links[0].Href)
-	if err != nil {
+	if _, err := job.WaitContext(ctx); err != nil {
 		return nil, err
 	}
 
-	return status, nil
+	return job, nil
 }
 
 // UpdateAWSNativeAccount updates the configuration of a AWS Native account. The following values, from PATCH /internal/aws/account/{id} are options for the config:
@@ -743,15 +778,23 @@ func (c *Credentials) RemoveAWSAccount(awsAccountName string, deleteExistingSnap
 //	  "disasterRecoveryArchivalLocationId": "string"
 //	}
 func (c *Credentials) UpdateAWSNativeAccount(archiveName string, config map[string]interface{}, timeout ...int) (*UpdateAWSNative, error) {
+	return c.UpdateAWSNativeAccountContext(context.Background(), archiveName, config, timeout...)
+}
+
+// UpdateAWSNativeAccountContext is the same as UpdateAWSNativeAccount with the addition of a context.Context that is used to cancel in-flight requests.
+func (c *Credentials) UpdateAWSNativeAccountContext(ctx context.Context, archiveName string, config map[string]interface{}, timeout ...int) (*UpdateAWSNative, error) {
+
+	ctx, span := c.startSpan(ctx, "UpdateAWSNativeAccount")
+	defer span.End()
 
 	httpTimeout := httpTimeout(timeout)
 
-	awsAccountSummary, err := c.AWSAccountSummary(archiveName, httpTimeout)
+	awsAccountSummary, err := c.AWSAccountSummaryContext(ctx, archiveName, httpTimeout)
 	if err != nil {
 		return nil, err
 	}
 
-	patchAPIRequest, err := c.Patch("internal", fmt.Sprintf("/aws/account/%s", awsAccountSummary.ID), config, httpTimeout)
+	patchAPIRequest, err := c.PatchContext(ctx, "internal", fmt.Sprintf("/aws/account/%s", awsAccountSummary.ID), config, httpTimeout)
 	if err != nil {
 		return nil, err
 	}
@@ -779,10 +822,18 @@ func (c *Credentials) UpdateAWSNativeAccount(archiveName string, config map[stri
 //
 // The function will return one of the following:
 //
-//   - No change required. The '{archiveName}' archive location is already configured on the Rubrik cluster.
+//   - An error matching ErrNoChangeRequired: No change required. The '{archiveName}' archive location is already configured on the Rubrik cluster.
 //
 //   - The full API response for POST /internal/archive/object_store.
 func (c *Credentials) AWSS3CloudOutRSA(awsBucketName, storageClass, archiveName, awsRegion, awsAccessKey, awsSecretKey, rsaKey string, timeout ...int) (interface{}, error) {
+	return c.AWSS3CloudOutRSAContext(context.Background(), awsBucketName, storageClass, archiveName, awsRegion, awsAccessKey, awsSecretKey, rsaKey, timeout...)
+}
+
+// AWSS3CloudOutRSAContext is the same as AWSS3CloudOutRSA with the addition of a context.Context that is used to cancel in-flight requests and any polling.
+func (c *Credentials) AWSS3CloudOutRSAContext(ctx context.Context, awsBucketName, storageClass, archiveName, awsRegion, awsAccessKey, awsSecretKey, rsaKey string, timeout ...int) (interface{}, error) {
+
+	ctx, span := c.startSpan(ctx, "AWSS3CloudOutRSA")
+	defer span.End()
 
 	httpTimeout := httpTimeout(timeout)
 
@@ -839,7 +890,7 @@ func (c *Credentials) AWSS3CloudOutRSA(awsBucketName, storageClass, archiveName,
 	redactedConfig["accessKey"] = awsAccessKey
 	redactedConfig["objectStoreType"] = "S3"
 
-	archivesOnCluster, err := c.CloudObjectStore(httpTimeout)
+	archivesOnCluster, err := c.CloudObjectStoreContext(ctx, httpTimeout)
 	if err != nil {
 		return "", err
 	}
@@ -858,7 +909,7 @@ func (c *Credentials) AWSS3CloudOutRSA(awsBucketName, storageClass, archiveName,
 		archivePresent := reflect.DeepEqual(redactedConfig, compareRedactedConfig)
 
 		if archivePresent {
-			return fmt.Sprintf("No change required. The '%s' archive location is already configured on the Rubrik cluster.", archiveName), nil
+			return nil, newError(ErrNoChangeRequired, "No change required. The '%s' archive location is already configured on the Rubrik cluster.", archiveName)
 		}
 
 		if v.Definition.ObjectStoreType == "S3" && v.Definition.Name == archiveName {
@@ -867,12 +918,12 @@ func (c *Credentials) AWSS3CloudOutRSA(awsBucketName, storageClass, archiveName,
 
 	}
 
-	apiRequest, err := c.Post("internal", "/archive/object_store", config, httpTimeout)
+	apiRequest, err := c.PostContext(ctx, "internal", "/archive/object_store", config, httpTimeout)
 	if err != nil {
 		return "", err
 	}
 
-	status, err := c.JobStatus(fmt.Sprintf("https://%s/api/internal/archive/location/job/connect/%s", c.NodeIP, apiRequest.(map[string]interface{})["jobInstanceId"].(string)), httpTimeout)
+	status, err := c.JobStatusContext(ctx, fmt.Sprintf("https://%s/api/internal/archive/location/job/connect/%s", c.NodeIP, apiRequest.(map[string]interface{})["jobInstanceId"].(string)), httpTimeout)
 	if err != nil {
 		return nil, err
 	}
@@ -883,10 +934,18 @@ func (c *Credentials) AWSS3CloudOutRSA(awsBucketName, storageClass, archiveName,
 
 // CloudObjectStore retrieves all archive locations configured on the Rubik cluster.
 func (c *Credentials) CloudObjectStore(timeout ...int) (*CloudObjectStore, error) {
+	return c.CloudObjectStoreContext(context.Background(), timeout...)
+}
+
+// CloudObjectStoreContext is the same as CloudObjectStore with the addition of a context.Context that is used to cancel in-flight requests.
+func (c *Credentials) CloudObjectStoreContext(ctx context.Context, timeout ...int) (*CloudObjectStore, error) {
+
+	ctx, span := c.startSpan(ctx, "CloudObjectStore")
+	defer span.End()
 
 	httpTimeout := httpTimeout(timeout)
 
-	apiArchivesOnCluster, err := c.Get("internal", "/archive/object_store", httpTimeout)
+	apiArchivesOnCluster, err := c.getAllContext(ctx, "internal", "/archive/object_store", httpTimeout)
 	if err != nil {
 		return nil, err
 	}
@@ -904,10 +963,18 @@ func (c *Credentials) CloudObjectStore(timeout ...int) (*CloudObjectStore, error
 
 // AWSAccountSummary retrieves all information from an AWS Native Account.
 func (c *Credentials) AWSAccountSummary(awsAccountName string, timeout ...int) (*CurrentAWSAccountID, error) {
+	return c.AWSAccountSummaryContext(context.Background(), awsAccountName, timeout...)
+}
+
+// AWSAccountSummaryContext is the same as AWSAccountSummary with the addition of a context.Context that is used to cancel in-flight requests.
+func (c *Credentials) AWSAccountSummaryContext(ctx context.Context, awsAccountName string, timeout ...int) (*CurrentAWSAccountID, error) {
+
+	ctx, span := c.startSpan(ctx, "AWSAccountSummary")
+	defer span.End()
 
 	httpTimeout := httpTimeout(timeout)
 
-	apiAWSAccounts, err := c.Get("internal", "/aws/account", httpTimeout)
+	apiAWSAccounts, err := c.getAllContext(ctx, "internal", "/aws/account", httpTimeout)
 	if err != nil {
 		return nil, err
 	}
@@ -926,10 +993,10 @@ func (c *Credentials) AWSAccountSummary(awsAccountName string, timeout ...int) (
 		}
 	}
 	if len(accountID) == 0 {
-		return nil, fmt.Errorf("The %s AWS Native Account was not found on the Rubrik cluster", awsAccountName)
+		return nil, newError(ErrNotFound, "The %s AWS Native Account was not found on the Rubrik cluster", awsAccountName)
 	}
 
-	apiAWSAccountsID, err := c.Get("internal", fmt.Sprintf("/aws/account/%s", accountID), httpTimeout)
+	apiAWSAccountsID, err := c.GetContext(ctx, "internal", fmt.Sprintf("/aws/account/%s", accountID), httpTimeout)
 	if err != nil {
 		return nil, err
 	}
@@ -947,11 +1014,19 @@ func (c *Credentials) AWSAccountSummary(awsAccountName string, timeout ...int) (
 
 // RemoveArchiveLocation deletes the archival location from the SLA Domains that reference it and expire all snapshots at the archival location
 func (c *Credentials) RemoveArchiveLocation(archiveName string, timeout ...int) (*JobStatus, error) {
+	return c.RemoveArchiveLocationContext(context.Background(), archiveName, timeout...)
+}
+
+// RemoveArchiveLocationContext is the same as RemoveArchiveLocation with the addition of a context.Context that is used to cancel in-flight requests.
+func (c *Credentials) RemoveArchiveLocationContext(ctx context.Context, archiveName string, timeout ...int) (*JobStatus, error) {
+
+	ctx, span := c.startSpan(ctx, "RemoveArchiveLocation")
+	defer span.End()
 
 	httpTimeout := httpTimeout(timeout)
 
 	// Search the Rubrik cluster for all current archive locations
-	currentArchivesRequest, err := c.Get("internal", fmt.Sprintf("/archive/location?name=%s", archiveName), httpTimeout)
+	currentArchivesRequest, err := c.getAllContext(ctx, "internal", fmt.Sprintf("/archive/location?name=%s", archiveName), httpTimeout)
 	if err != nil {
 		return nil, err
 	}
@@ -973,11 +1048,11 @@ func (c *Credentials) RemoveArchiveLocation(archiveName string, timeout ...int)
 	}
 
 	if archiveID == "" {
-		return nil, fmt.Errorf("No change required. The Rubrik cluster does not contain a archive location named '%s'", archiveName)
+		return nil, newError(ErrNoChangeRequired, "No change required. The Rubrik cluster does not contain a archive location named '%s'", archiveName)
 	}
 
 	// Pause archive activity on the archive location before deleting
-	_, pauseErr := c.Post("internal", fmt.Sprintf("/archive/location/%s/owner/pause", archiveID), httpTimeout)
+	_, pauseErr := c.PostContext(ctx, "internal", fmt.Sprintf("/archive/location/%s/owner/pause", archiveID), httpTimeout)
 	if pauseErr != nil {
 		// If the archive location is already paused do not return an error message
 		if strings.Contains(pauseErr.Error(), "already paused") != true {
@@ -986,7 +1061,7 @@ func (c *Credentials) RemoveArchiveLocation(archiveName string, timeout ...int)
 
 	}
 
-	deleteAPIRequest, err := c.Delete("internal", fmt.Sprintf("/archive/location/%s", archiveID), httpTimeout)
+	deleteAPIRequest, err := c.DeleteContext(ctx, "internal", fmt.Sprintf("/archive/location/%s", archiveID), httpTimeout)
 	if err != nil {
 		return nil, err
 	}
@@ -1052,11 +1127,19 @@ func (c *Credentials) RemoveArchiveLocation(archiveName string, timeout ...int)
 //	   }
 //	 }
 func (c *Credentials) UpdateCloudArchiveLocation(archiveName string, config map[string]interface{}, timeout ...int) (*UpdateArchiveLocations, error) {
+	return c.UpdateCloudArchiveLocationContext(context.Background(), archiveName, config, timeout...)
+}
+
+// UpdateCloudArchiveLocationContext is the same as UpdateCloudArchiveLocation with the addition of a context.Context that is used to cancel in-flight requests.
+func (c *Credentials) UpdateCloudArchiveLocationContext(ctx context.Context, archiveName string, config map[string]interface{}, timeout ...int) (*UpdateArchiveLocations, error) {
+
+	ctx, span := c.startSpan(ctx, "UpdateCloudArchiveLocation")
+	defer span.End()
 
 	httpTimeout := httpTimeout(timeout)
 
 	// Search the Rubrik cluster for all current archive locations
-	currentArchivesRequest, err := c.Get("internal", fmt.Sprintf("/archive/location?name=%s", archiveName), httpTimeout)
+	currentArchivesRequest, err := c.getAllContext(ctx, "internal", fmt.Sprintf("/archive/location?name=%s", archiveName), httpTimeout)
 	if err != nil {
 		return nil, err
 	}
@@ -1077,10 +1160,10 @@ func (c *Credentials) UpdateCloudArchiveLocation(archiveName string, config map[
 		}
 	}
 	if archiveID == "" {
-		return nil, fmt.Errorf("No change required. The Rubrik cluster does not contain a archive location named '%s'", archiveName)
+		return nil, newError(ErrNoChangeRequired, "No change required. The Rubrik cluster does not contain a archive location named '%s'", archiveName)
 	}
 
-	patchAPIRequest, err := c.Patch("internal", fmt.Sprintf("/archive/object_store/%s", archiveID), config, httpTimeout)
+	patchAPIRequest, err := c.PatchContext(ctx, "internal", fmt.Sprintf("/archive/object_store/%s", archiveID), config, httpTimeout)
 	if err != nil {
 		return nil, err
 	}
@@ -1108,10 +1191,18 @@ func (c *Credentials) UpdateCloudArchiveLocation(archiveName string, config map[
 //
 // The function will return one of the following:
 //
-//   - No change required. The '{archiveName}' archive location is already configured on the Rubrik cluster.
+//   - An error matching ErrNoChangeRequired: No change required. The '{archiveName}' archive location is already configured on the Rubrik cluster.
 //
 //   - The full API response for POST /internal/archive/object_store/{archiveID}
 func (c *Credentials) AWSS3CloudOutKMS(awsBucketName, storageClass, archiveName, awsRegion, awsAccessKey, awsSecretKey, kmsMasterKeyID string, timeout ...int) (interface{}, error) {
+	return c.AWSS3CloudOutKMSContext(context.Background(), awsBucketName, storageClass, archiveName, awsRegion, awsAccessKey, awsSecretKey, kmsMasterKeyID, timeout...)
+}
+
+// AWSS3CloudOutKMSContext is the same as AWSS3CloudOutKMS with the addition of a context.Context that is used to cancel in-flight requests and any polling.
+func (c *Credentials) AWSS3CloudOutKMSContext(ctx context.Context, awsBucketName, storageClass, archiveName, awsRegion, awsAccessKey, awsSecretKey, kmsMasterKeyID string, timeout ...int) (interface{}, error) {
+
+	ctx, span := c.startSpan(ctx, "AWSS3CloudOutKMS")
+	defer span.End()
 
 	httpTimeout := httpTimeout(timeout)
 
@@ -1168,7 +1259,7 @@ func (c *Credentials) AWSS3CloudOutKMS(awsBucketName, storageClass, archiveName,
 	redactedConfig["accessKey"] = awsAccessKey
 	redactedConfig["objectStoreType"] = "S3"
 
-	archivesOnCluster, err := c.Get("internal", "/archive/object_store", httpTimeout)
+	archivesOnCluster, err := c.getAllContext(ctx, "internal", "/archive/object_store", httpTimeout)
 	if err != nil {
 		return nil, err
 	}
@@ -1182,7 +1273,7 @@ func (c *Credentials) AWSS3CloudOutKMS(awsBucketName, storageClass, archiveName,
 		archivePresent := reflect.DeepEqual(redactedConfig, archiveDefinition)
 
 		if archivePresent {
-			return fmt.Sprintf("No change required. The '%s' archive location is already configured on the Rubrik cluster.", archiveName), nil
+			return nil, newError(ErrNoChangeRequired, "No change required. The '%s' archive location is already configured on the Rubrik cluster.", archiveName)
 		}
 
 		if archiveDefinition.(map[string]interface{})["objectStoreType"] == "S3" && archiveDefinition.(map[string]interface{})["name"] == archiveName {
@@ -1191,12 +1282,12 @@ func (c *Credentials) AWSS3CloudOutKMS(awsBucketName, storageClass, archiveName,
 
 	}
 
-	apiRequest, err := c.Post("internal", "/archive/object_store", config, httpTimeout)
+	apiRequest, err := c.PostContext(ctx, "internal", "/archive/object_store", config, httpTimeout)
 	if err != nil {
 		return nil, err
 	}
 
-	status, err := c.JobStatus(fmt.Sprintf("https://%s/api/internal/archive/location/job/connect/%s", c.NodeIP, apiRequest.(map[string]interface{})["jobInstanceId"].(string)), httpTimeout)
+	status, err := c.JobStatusContext(ctx, fmt.Sprintf("https://%s/api/internal/archive/location/job/connect/%s", c.NodeIP, apiRequest.(map[string]interface{})["jobInstanceId"].(string)), httpTimeout)
 	if err != nil {
 		return nil, err
 	}
@@ -1210,10 +1301,18 @@ func (c *Credentials) AWSS3CloudOutKMS(awsBucketName, storageClass, archiveName,
 //
 // The function will return one of the following:
 //
-//   - No change required. The '{archiveName}' archive location is already configured for CloudOn.
+//   - An error matching ErrNoChangeRequired: No change required. The '{archiveName}' archive location is already configured for CloudOn.
 //
 //   - The full API response for PATCH /internal/archive/object_store.
 func (c *Credentials) AWSS3CloudOn(archiveName, vpcID, subnetID, securityGroupID string, timeout ...int) (*CloudOn, error) {
+	return c.AWSS3CloudOnContext(context.Background(), archiveName, vpcID, subnetID, securityGroupID, timeout...)
+}
+
+// AWSS3CloudOnContext is the same as AWSS3CloudOn with the addition of a context.Context that is used to cancel in-flight requests.
+func (c *Credentials) AWSS3CloudOnContext(ctx context.Context, archiveName, vpcID, subnetID, securityGroupID string, timeout ...int) (*CloudOn, error) {
+
+	ctx, span := c.startSpan(ctx, "AWSS3CloudOn")
+	defer span.End()
 
 	httpTimeout := httpTimeout(timeout)
 
@@ -1223,7 +1322,7 @@ func (c *Credentials) AWSS3CloudOn(archiveName, vpcID, subnetID, securityGroupID
 	config["defaultComputeNetworkConfig"].(map[string]string)["subnetId"] = subnetID
 	config["defaultComputeNetworkConfig"].(map[string]string)["securityGroupId"] = securityGroupID
 
-	archivesOnCluster, err := c.CloudObjectStore(httpTimeout)
+	archivesOnCluster, err := c.CloudObjectStoreContext(ctx, httpTimeout)
 	if err != nil {
 		return nil, err
 	}
@@ -1234,10 +1333,10 @@ func (c *Credentials) AWSS3CloudOn(archiveName, vpcID, subnetID, securityGroupID
 
 			archivePresent := reflect.DeepEqual(v.Definition.DefaultComputeNetworkConfig, config["defaultComputeNetworkConfig"])
 			if archivePresent {
-				return nil, fmt.Errorf("No change required. The '%s' archive location is already configured for CloudOn", archiveName)
+				return nil, newError(ErrNoChangeRequired, "No change required. The '%s' archive location is already configured for CloudOn", archiveName)
 			}
 
-			apiRequest, err := c.Patch("internal", fmt.Sprintf("/archive/object_store/%s", v.ID), config, httpTimeout)
+			apiRequest, err := c.PatchContext(ctx, "internal", fmt.Sprintf("/archive/object_store/%s", v.ID), config, httpTimeout)
 			if err != nil {
 				return nil, err
 			}
@@ -1253,7 +1352,7 @@ func (c *Credentials) AWSS3CloudOn(archiveName, vpcID, subnetID, securityGroupID
 		}
 	}
 
-	return nil, fmt.Errorf("The Rubrik cluster does not have an archive location named '%s'", archiveName)
+	return nil, newError(ErrNotFound, "The Rubrik cluster does not have an archive location named '%s'", archiveName)
 
 }
 
@@ -1265,10 +1364,18 @@ func (c *Credentials) AWSS3CloudOn(archiveName, vpcID, subnetID, securityGroupID
 //
 // The function will return one of the following:
 //
-//   - No change required. The '{archiveName}' archive location is already configured on the Rubrik cluster.
+//   - An error matching ErrNoChangeRequired: No change required. The '{archiveName}' archive location is already configured on the Rubrik cluster.
 //
 //   - The full API response for POST /internal/archive/object_store.
 func (c *Credentials) AzureCloudOut(container, azureAccessKey, storageAccountName, archiveName, instanceType, rsaKey string, timeout ...int) (interface{}, error) {
+	return c.AzureCloudOutContext(context.Background(), container, azureAccessKey, storageAccountName, archiveName, instanceType, rsaKey, timeout...)
+}
+
+// AzureCloudOutContext is the same as AzureCloudOut with the addition of a context.Context that is used to cancel in-flight requests and any polling.
+func (c *Credentials) AzureCloudOutContext(ctx context.Context, container, azureAccessKey, storageAccountName, archiveName, instanceType, rsaKey string, timeout ...int) (interface{}, error) {
+
+	ctx, span := c.startSpan(ctx, "AzureCloudOut")
+	defer span.End()
 
 	httpTimeout := httpTimeout(timeout)
 
@@ -1314,7 +1421,7 @@ func (c *Credentials) AzureCloudOut(container, azureAccessKey, storageAccountNam
 		redactedConfig["endpoint"] = "core.chinacloudapi.cn"
 	}
 
-	archivesOnCluster, err := c.Get("internal", "/archive/object_store", httpTimeout)
+	archivesOnCluster, err := c.getAllContext(ctx, "internal", "/archive/object_store", httpTimeout)
 	if err != nil {
 		return nil, err
 	}
@@ -1329,7 +1436,7 @@ func (c *Credentials) AzureCloudOut(container, azureAccessKey, storageAccountNam
 		archivePresent := reflect.DeepEqual(redactedConfig, archiveDefinition)
 
 		if archivePresent {
-			return fmt.Sprintf("No change required. The '%s' archive location is already configured on the Rubrik cluster.", archiveName), nil
+			return nil, newError(ErrNoChangeRequired, "No change required. The '%s' archive location is already configured on the Rubrik cluster.", archiveName)
 		}
 
 		if archiveDefinition.(map[string]interface{})["objectStoreType"] == "Azure" && archiveDefinition.(map[string]interface{})["name"] == archiveName {
@@ -1338,12 +1445,12 @@ func (c *Credentials) AzureCloudOut(container, azureAccessKey, storageAccountNam
 
 	}
 
-	apiRequest, err := c.Post("internal", "/archive/object_store", config, httpTimeout)
+	apiRequest, err := c.PostContext(ctx, "internal", "/archive/object_store", config, httpTimeout)
 	if err != nil {
 		return "", err
 	}
 
-	status, err := c.JobStatus(fmt.Sprintf("https://%s/api/internal/archive/location/job/connect/%s", c.NodeIP, apiRequest.(map[string]interface{})["jobInstanceId"].(string)), httpTimeout)
+	status, err := c.JobStatusContext(ctx, fmt.Sprintf("https://%s/api/internal/archive/location/job/connect/%s", c.NodeIP, apiRequest.(map[string]interface{})["jobInstanceId"].(string)), httpTimeout)
 	if err != nil {
 		return nil, err
 	}
@@ -1363,10 +1470,18 @@ func (c *Credentials) AzureCloudOut(container, azureAccessKey, storageAccountNam
 //
 // The function will return one of the following:
 //
-//   - No change required. The '{archiveName}' archive location is already configured for CloudOn.
+//   - An error matching ErrNoChangeRequired: No change required. The '{archiveName}' archive location is already configured for CloudOn.
 //
 //   - The full API response for PATCH /internal/archive/object_store.
 func (c *Credentials) AzureCloudOn(archiveName, container, storageAccountName, applicationID, applicationKey, directoryID, region, virtualNetworkID, subnetName, securityGroupID string, timeout ...int) (*CloudOn, error) {
+	return c.AzureCloudOnContext(context.Background(), archiveName, container, storageAccountName, applicationID, applicationKey, directoryID, region, virtualNetworkID, subnetName, securityGroupID, timeout...)
+}
+
+// AzureCloudOnContext is the same as AzureCloudOn with the addition of a context.Context that is used to cancel in-flight requests.
+func (c *Credentials) AzureCloudOnContext(ctx context.Context, archiveName, container, storageAccountName, applicationID, applicationKey, directoryID, region, virtualNetworkID, subnetName, securityGroupID string, timeout ...int) (*CloudOn, error) {
+
+	ctx, span := c.startSpan(ctx, "AzureCloudOn")
+	defer span.End()
 
 	httpTimeout := httpTimeout(timeout)
 
@@ -1446,7 +1561,7 @@ func (c *Credentials) AzureCloudOn(archiveName, container, storageAccountName, a
 	redactedConfig["defaultComputeNetworkConfig"].(map[string]string)["vNetId"] = virtualNetworkID
 	redactedConfig["defaultComputeNetworkConfig"].(map[string]string)["securityGroupId"] = securityGroupID
 
-	archivesOnCluster, err := c.Get("internal", "/archive/object_store", httpTimeout)
+	archivesOnCluster, err := c.getAllContext(ctx, "internal", "/archive/object_store", httpTimeout)
 	if err != nil {
 		return nil, err
 	}
@@ -1458,11 +1573,11 @@ func (c *Credentials) AzureCloudOn(archiveName, container, storageAccountName, a
 
 			archivePresent := reflect.DeepEqual(archiveDefinition.(map[string]interface{})["defaultComputeNetworkConfig"], config["defaultComputeNetworkConfig"])
 			if archivePresent {
-				return nil, fmt.Errorf("No change required. The '%s' archive location is already configured for CloudOn", archiveName)
+				return nil, newError(ErrNoChangeRequired, "No change required. The '%s' archive location is already configured for CloudOn", archiveName)
 			}
 
 			archiveID := (v.(interface{}).(map[string]interface{})["id"])
-			apiRequest, err := c.Patch("internal", fmt.Sprintf("/archive/object_store/%s", archiveID), config, httpTimeout)
+			apiRequest, err := c.PatchContext(ctx, "internal", fmt.Sprintf("/archive/object_store/%s", archiveID), config, httpTimeout)
 			if err != nil {
 				return nil, err
 			}
@@ -1479,6 +1594,6 @@ func (c *Credentials) AzureCloudOn(archiveName, container, storageAccountName, a
 		}
 
 	}
-	return nil, fmt.Errorf("The Rubrik cluster does not have an archive location named '%s'", archiveName)
+	return nil, newError(ErrNotFound, "The Rubrik cluster does not have an archive location named '%s'", archiveName)
 
 }
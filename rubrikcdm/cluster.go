@@ -14,10 +14,11 @@
 package rubrikcdm
 
 import (
+	"context"
 	"errors"
 	"fmt"
+	"math"
 	"reflect"
-	"strconv"
 	"strings"
 	"time"
 
@@ -29,6 +30,19 @@ type ClusterVersion struct {
 	Version string `json:"version"`
 }
 
+// ClusterNodes corresponds to GET /internal/cluster/me/node
+type ClusterNodes struct {
+	HasMore bool `json:"hasMore"`
+	Data    []struct {
+		ID              string `json:"id"`
+		BrikID          string `json:"brikId"`
+		Status          string `json:"status"`
+		IPAddress       string `json:"ipAddress"`
+		NeedsInspection bool   `json:"needsInspection"`
+	} `json:"data"`
+	Total int `json:"total"`
+}
+
 // EndUserAuthorization corresponds to POST /internal/authorization/role/end_user
 type EndUserAuthorization struct {
 	HasMore bool `json:"hasMore"`
@@ -85,10 +99,18 @@ type SMTP struct {
 
 // ClusterVersion returns the CDM version of the Rubrik cluster.
 func (c *Credentials) ClusterVersion(timeout ...int) (string, error) {
+	return c.ClusterVersionContext(context.Background(), timeout...)
+}
+
+// ClusterVersionContext is the same as ClusterVersion with the addition of a context.Context that is used to cancel in-flight requests.
+func (c *Credentials) ClusterVersionContext(ctx context.Context, timeout ...int) (string, error) {
+
+	ctx, span := c.startSpan(ctx, "ClusterVersion")
+	defer span.End()
 
 	httpTimeout := httpTimeout(timeout)
 
-	apiRequest, err := c.Get("v1", "/cluster/me/version", httpTimeout)
+	apiRequest, err := c.GetContext(ctx, "v1", "/cluster/me/version", httpTimeout)
 	if err != nil {
 		return "", err
 	}
@@ -106,19 +128,31 @@ func (c *Credentials) ClusterVersion(timeout ...int) (string, error) {
 // ClusterVersionCheck is used to determine if the Rubrik cluster is using running an earlier release than the provided CDM "clusterVersion".
 // If the CDM version is an earlier release than the "clusterVersion", the following message error message is thrown:
 // Error: The Rubrik cluster must be running CDM version {clusterVersion} or later.
+//
+// The returned error matches ErrUnsupportedVersion. Use ClusterCDMVersion() to compare patch releases.
 func (c *Credentials) ClusterVersionCheck(clusterVersion float64, timeout ...int) error {
+	return c.ClusterVersionCheckContext(context.Background(), clusterVersion, timeout...)
+}
+
+// ClusterVersionCheckContext is the same as ClusterVersionCheck with the addition of a context.Context that is used to cancel in-flight requests.
+func (c *Credentials) ClusterVersionCheckContext(ctx context.Context, clusterVersion float64, timeout ...int) error {
+
+	ctx, span := c.startSpan(ctx, "ClusterVersionCheck")
+	defer span.End()
 
 	httpTimeout := httpTimeout(timeout)
 
-	currentClusterVersion, err := c.ClusterVersion(httpTimeout)
+	currentClusterVersion, err := c.ClusterCDMVersionContext(ctx, httpTimeout)
 	if err != nil {
 		return err
 	}
 
-	convertedClusterVersion, _ := strconv.ParseFloat(currentClusterVersion[:3], 2)
+	// The "clusterVersion" only contains the major and minor version (ex. 4.2)
+	major := int(clusterVersion)
+	minimum := CDMVersion{Major: major, Minor: int(math.Round((clusterVersion - float64(major)) * 10))}
 
-	if convertedClusterVersion < clusterVersion {
-		return fmt.Errorf("The Rubrik cluster must be running CDM version %.1f or later", clusterVersion)
+	if currentClusterVersion.AtLeast(minimum) == false {
+		return newError(ErrUnsupportedVersion, "The Rubrik cluster must be running CDM version %.1f or later", clusterVersion)
 	}
 
 	return nil
@@ -126,18 +160,26 @@ func (c *Credentials) ClusterVersionCheck(clusterVersion float64, timeout ...int
 
 // ClusterNodeIP returns all Node IPs in the Rubrik cluster.
 func (c *Credentials) ClusterNodeIP(timeout ...int) ([]string, error) {
+	return c.ClusterNodeIPContext(context.Background(), timeout...)
+}
+
+// ClusterNodeIPContext is the same as ClusterNodeIP with the addition of a context.Context that is used to cancel in-flight requests.
+func (c *Credentials) ClusterNodeIPContext(ctx context.Context, timeout ...int) ([]string, error) {
+
+	ctx, span := c.startSpan(ctx, "ClusterNodeIP")
+	defer span.End()
 
 	httpTimeout := httpTimeout(timeout)
 
-	apiRequest, err := c.Get("internal", "/cluster/me/node", httpTimeout)
+	clusterNodes, err := GetIntoContext[ClusterNodes](ctx, c, "internal", "/cluster/me/node", httpTimeout)
 	if err != nil {
 		return nil, err
 	}
 
 	var nodeList []string
 
-	for _, v := range apiRequest.(map[string]interface{})["data"].([]interface{}) {
-		nodeList = append(nodeList, v.(interface{}).(map[string]interface{})["ipAddress"].(string))
+	for _, node := range clusterNodes.Data {
+		nodeList = append(nodeList, node.IPAddress)
 	}
 
 	return nodeList, nil
@@ -145,18 +187,26 @@ func (c *Credentials) ClusterNodeIP(timeout ...int) ([]string, error) {
 
 // ClusterNodeName returns the name of all nodes in the Rubrik cluster.
 func (c *Credentials) ClusterNodeName(timeout ...int) ([]string, error) {
+	return c.ClusterNodeNameContext(context.Background(), timeout...)
+}
+
+// ClusterNodeNameContext is the same as ClusterNodeName with the addition of a context.Context that is used to cancel in-flight requests.
+func (c *Credentials) ClusterNodeNameContext(ctx context.Context, timeout ...int) ([]string, error) {
+
+	ctx, span := c.startSpan(ctx, "ClusterNodeName")
+	defer span.End()
 
 	httpTimeout := httpTimeout(timeout)
 
-	apiRequest, err := c.Get("internal", "/cluster/me/node", httpTimeout)
+	clusterNodes, err := GetIntoContext[ClusterNodes](ctx, c, "internal", "/cluster/me/node", httpTimeout)
 	if err != nil {
 		return nil, err
 	}
 
 	var nodeName []string
 
-	for _, v := range apiRequest.(map[string]interface{})["data"].([]interface{}) {
-		nodeName = append(nodeName, v.(interface{}).(map[string]interface{})["id"].(string))
+	for _, node := range clusterNodes.Data {
+		nodeName = append(nodeName, node.ID)
 	}
 
 	return nodeName, nil
@@ -164,13 +214,21 @@ func (c *Credentials) ClusterNodeName(timeout ...int) ([]string, error) {
 
 // ClusterBootstrapStatus checks whether the cluster has been bootstrapped.
 func (c *Credentials) ClusterBootstrapStatus(timeout ...int) (bool, error) {
+	return c.ClusterBootstrapStatusContext(context.Background(), timeout...)
+}
+
+// ClusterBootstrapStatusContext is the same as ClusterBootstrapStatus with the addition of a context.Context that is used to cancel in-flight requests and any polling.
+func (c *Credentials) ClusterBootstrapStatusContext(ctx context.Context, timeout ...int) (bool, error) {
+
+	ctx, span := c.startSpan(ctx, "ClusterBootstrapStatus")
+	defer span.End()
 
 	httpTimeout := httpTimeout(timeout)
 
 	numberOfAttempts := 0
 	for {
 		numberOfAttempts++
-		apiRequest, err := c.Get("internal", "/node_management/is_bootstrapped", httpTimeout)
+		apiRequest, err := c.GetContext(ctx, "internal", "/node_management/is_bootstrapped", httpTimeout)
 		if err != nil {
 
 			// Give the cluster 4 minutes to start responding to API calls before returning an error
@@ -197,48 +255,55 @@ func (c *Credentials) ClusterBootstrapStatus(timeout ...int) (bool, error) {
 		if err == nil {
 			return apiRequest.(map[string]interface{})["value"].(bool), nil
 		}
-		time.Sleep(10 * time.Second)
+		if err := sleepContext(ctx, 10*time.Second); err != nil {
+			return false, err
+		}
 
 	}
 
 }
 
-// EndUserAuthorization assigns an End User account privileges for a VMware virtual machine. VMware is currently the only
+// EndUserAuthorization assigns an End User account privileges for a VMware virtual machine. vmware is currently the only
 // supported "objectType"
 //
 // The function will return one of the following:
 //
-//	No change required. The End User '{endUser}' is already authorized to interact with the '{objectName}' VM.
+//	An error matching ErrNoChangeRequired: No change required. The End User '{endUser}' is already authorized to interact with the '{objectName}' VM.
 //
 //	The full API response for POST /internal/authorization/role/end_user
-func (c *Credentials) EndUserAuthorization(objectName, endUser, objectType string, timeout ...int) (*EndUserAuthorization, error) {
+func (c *Credentials) EndUserAuthorization(objectName, endUser string, objectType ObjectType, timeout ...int) (*EndUserAuthorization, error) {
+	return c.EndUserAuthorizationContext(context.Background(), objectName, endUser, objectType, timeout...)
+}
 
-	httpTimeout := httpTimeout(timeout)
+// EndUserAuthorizationContext is the same as EndUserAuthorization with the addition of a context.Context that is used to cancel in-flight requests.
+func (c *Credentials) EndUserAuthorizationContext(ctx context.Context, objectName, endUser string, objectType ObjectType, timeout ...int) (*EndUserAuthorization, error) {
 
-	validObjectType := map[string]bool{
-		"VMware": true,
-	}
+	ctx, span := c.startSpan(ctx, "EndUserAuthorization")
+	defer span.End()
+
+	httpTimeout := httpTimeout(timeout)
 
-	if validObjectType[objectType] == false {
-		return nil, errors.New("The 'objectType' must be 'VMware'")
+	objectType, err := checkObjectType("EndUserAuthorization", objectType)
+	if err != nil {
+		return nil, err
 	}
 
-	vmID, err := c.ObjectID(objectName, objectType, httpTimeout)
+	vmID, err := c.ObjectIDContext(ctx, objectName, objectType, httpTimeout)
 	if err != nil {
 		return nil, err
 	}
 
-	userLookup, err := c.Get("internal", fmt.Sprintf("/user?username=%s", endUser))
+	userLookup, err := c.GetContext(ctx, "internal", fmt.Sprintf("/user?username=%s", endUser))
 	if err != nil {
 		return nil, err
 	}
 
 	if len(userLookup.([]interface{})) == 0 {
-		return nil, fmt.Errorf("The Rubrik cluster does not contain a End User account named '%s'", endUser)
+		return nil, newError(ErrNotFound, "The Rubrik cluster does not contain a End User account named '%s'", endUser)
 	}
 	userID := userLookup.([]interface{})[0].(map[string]interface{})["id"]
 
-	userAuthorization, err := c.Get("internal", fmt.Sprintf("/authorization/role/end_user?principals=%s", userID))
+	userAuthorization, err := c.GetContext(ctx, "internal", fmt.Sprintf("/authorization/role/end_user?principals=%s", userID))
 	if err != nil {
 		return nil, err
 	}
@@ -247,7 +312,7 @@ func (c *Credentials) EndUserAuthorization(objectName, endUser, objectType strin
 
 	for _, vm := range authorizedObjects.([]interface{}) {
 		if vm == vmID {
-			return nil, fmt.Errorf("No change required. The End User '%s' is already authorized to interact with the '%s' VM", endUser, objectName)
+			return nil, newError(ErrNoChangeRequired, "No change required. The End User '%s' is already authorized to interact with the '%s' VM", endUser, objectName)
 		}
 	}
 
@@ -256,7 +321,7 @@ func (c *Credentials) EndUserAuthorization(objectName, endUser, objectType strin
 	config["privileges"] = map[string]interface{}{}
 	config["privileges"].(map[string]interface{})["restore"] = []string{vmID}
 
-	apiRequest, err := c.Post("internal", "/authorization/role/end_user", config, httpTimeout)
+	apiRequest, err := c.PostContext(ctx, "internal", "/authorization/role/end_user", config, httpTimeout)
 	if err != nil {
 		return nil, err
 	}
@@ -284,10 +349,18 @@ func (c *Credentials) EndUserAuthorization(objectName, endUser, objectType strin
 //
 // The function will return one of the following:
 //
-//	No change required. The Rubrik cluster is already configured with '{timezone}' as it's timezone.
+//	An error matching ErrNoChangeRequired: No change required. The Rubrik cluster is already configured with '{timezone}' as it's timezone.
 //
 //	The full API response for POST /v1/cluster/me
 func (c *Credentials) ConfigureTimezone(timezone string, timeout ...int) (*ClusterProperties, error) {
+	return c.ConfigureTimezoneContext(context.Background(), timezone, timeout...)
+}
+
+// ConfigureTimezoneContext is the same as ConfigureTimezone with the addition of a context.Context that is used to cancel in-flight requests.
+func (c *Credentials) ConfigureTimezoneContext(ctx context.Context, timezone string, timeout ...int) (*ClusterProperties, error) {
+
+	ctx, span := c.startSpan(ctx, "ConfigureTimezone")
+	defer span.End()
 
 	httpTimeout := httpTimeout(timeout)
 
@@ -331,23 +404,24 @@ func (c *Credentials) ConfigureTimezone(timezone string, timeout ...int) (*Clust
 		return nil, fmt.Errorf("The 'timezone' must be 'America/Anchorage', 'America/Araguaina', 'America/Barbados', 'America/Chicago', 'America/Denver', 'America/Los_Angeles' 'America/Mexico_City', 'America/New_York', 'America/Noronha', 'America/Phoenix', 'America/Toronto', 'America/Vancouver', 'Asia/Bangkok', 'Asia/Dhaka', 'Asia/Dubai', 'Asia/Hong_Kong', 'Asia/Karachi', 'Asia/Kathmandu', 'Asia/Kolkata', 'Asia/Magadan', 'Asia/Singapore', 'Asia/Tokyo', 'Atlantic/Cape_Verde', 'Australia/Perth', 'Australia/Sydney', 'Europe/Amsterdam', 'Europe/Athens', 'Europe/London', 'Europe/Moscow', 'Pacific/Auckland', 'Pacific/Honolulu', 'Pacific/Midway', or 'UTC'")
 	}
 
-	clusterSummary, err := c.Get("v1", "/cluster/me", httpTimeout)
+	clusterSummary, err := c.GetContext(ctx, "v1", "/cluster/me", httpTimeout)
 	if err != nil {
 		return nil, err
 	}
 
 	if clusterSummary.(map[string]interface{})["timezone"].(map[string]interface{})["timezone"] == timezone {
-		return nil, fmt.Errorf("No change required. The Rubrik cluster is already configured with '%s' as it's timezone", timezone)
+		return nil, newError(ErrNoChangeRequired, "No change required. The Rubrik cluster is already configured with '%s' as it's timezone", timezone)
 	}
 
 	config := map[string]interface{}{}
 	config["timezone"] = map[string]string{}
 	config["timezone"].(map[string]string)["timezone"] = timezone
 
-	apiRequest, err := c.Patch("v1", "/cluster/me", config, httpTimeout)
+	apiRequest, err := c.PatchContext(ctx, "v1", "/cluster/me", config, httpTimeout)
 	if err != nil {
 		return nil, err
 	}
+	c.forgetClusterTimezone()
 
 	// Convert the API Response (map[string]interface{}) to a struct
 	var apiResponse ClusterProperties
@@ -364,14 +438,22 @@ func (c *Credentials) ConfigureTimezone(timezone string, timeout ...int) (*Clust
 //
 // The function will return one of the following:
 //
-//	No change required. The NTP server(s) {ntpServers} has already been added to the Rubrik cluster.
+//	An error matching ErrNoChangeRequired: No change required. The NTP server(s) {ntpServers} has already been added to the Rubrik cluster.
 //
 //	The full API response for POST /internal/cluster/me/ntp_server
 func (c *Credentials) ConfigureNTP(ntpServers []string, timeout ...int) (*StatusCode, error) {
+	return c.ConfigureNTPContext(context.Background(), ntpServers, timeout...)
+}
+
+// ConfigureNTPContext is the same as ConfigureNTP with the addition of a context.Context that is used to cancel in-flight requests.
+func (c *Credentials) ConfigureNTPContext(ctx context.Context, ntpServers []string, timeout ...int) (*StatusCode, error) {
+
+	ctx, span := c.startSpan(ctx, "ConfigureNTP")
+	defer span.End()
 
 	httpTimeout := httpTimeout(timeout)
 
-	clusterNTP, err := c.Get("internal", "/cluster/me/ntp_server")
+	clusterNTP, err := c.GetContext(ctx, "internal", "/cluster/me/ntp_server")
 	if err != nil {
 		return nil, err
 	}
@@ -394,7 +476,7 @@ func (c *Credentials) ConfigureNTP(ntpServers []string, timeout ...int) (*Status
 	}
 
 	if updateNTP {
-		apiRequest, err := c.Post("internal", "/cluster/me/ntp_server", ntpServers, httpTimeout)
+		apiRequest, err := c.PostContext(ctx, "internal", "/cluster/me/ntp_server", ntpServers, httpTimeout)
 		if err != nil {
 			return nil, err
 		}
@@ -409,7 +491,7 @@ func (c *Credentials) ConfigureNTP(ntpServers []string, timeout ...int) (*Status
 		return &apiResponse, nil
 	}
 
-	return nil, fmt.Errorf("No change required. The NTP server(s) %s has already been added to the Rubrik cluster", ntpServers)
+	return nil, newError(ErrNoChangeRequired, "No change required. The NTP server(s) %s has already been added to the Rubrik cluster", ntpServers)
 
 }
 
@@ -422,10 +504,18 @@ func (c *Credentials) ConfigureNTP(ntpServers []string, timeout ...int) (*Status
 //
 // The function will return one of the following:
 //
-//	No change required. The Rubrik cluster is already configured to use the syslog server '{syslogIP}' on port '{port}' using the '{protocol}' protocol.
+//	An error matching ErrNoChangeRequired: No change required. The Rubrik cluster is already configured to use the syslog server '{syslogIP}' on port '{port}' using the '{protocol}' protocol.
 //
 //	The full API response for POST /internal/syslog
 func (c *Credentials) ConfigureSyslog(syslogIP, protocol string, port float64, timeout ...int) (*Syslog, error) {
+	return c.ConfigureSyslogContext(context.Background(), syslogIP, protocol, port, timeout...)
+}
+
+// ConfigureSyslogContext is the same as ConfigureSyslog with the addition of a context.Context that is used to cancel in-flight requests.
+func (c *Credentials) ConfigureSyslogContext(ctx context.Context, syslogIP, protocol string, port float64, timeout ...int) (*Syslog, error) {
+
+	ctx, span := c.startSpan(ctx, "ConfigureSyslog")
+	defer span.End()
 
 	httpTimeout := httpTimeout(timeout)
 
@@ -443,7 +533,7 @@ func (c *Credentials) ConfigureSyslog(syslogIP, protocol string, port float64, t
 	config["protocol"] = protocol
 	config["port"] = port
 
-	clusterSyslog, err := c.Get("internal", "/syslog")
+	clusterSyslog, err := c.GetContext(ctx, "internal", "/syslog")
 	if err != nil {
 		return nil, err
 	}
@@ -465,18 +555,18 @@ func (c *Credentials) ConfigureSyslog(syslogIP, protocol string, port float64, t
 
 		}
 		if deleteSyslog {
-			_, err := c.Delete("internal", "/syslog/1")
+			_, err := c.DeleteContext(ctx, "internal", "/syslog/1")
 			if err != nil {
 				return nil, err
 			}
 
 		} else {
-			return nil, fmt.Errorf("No change required. The Rubrik cluster is already configured to use the syslog server '%s' on port '%d' using the '%s' protocol", syslogIP, int(port), protocol)
+			return nil, newError(ErrNoChangeRequired, "No change required. The Rubrik cluster is already configured to use the syslog server '%s' on port '%d' using the '%s' protocol", syslogIP, int(port), protocol)
 		}
 
 	}
 
-	apiRequest, err := c.Post("internal", "/syslog", config, httpTimeout)
+	apiRequest, err := c.PostContext(ctx, "internal", "/syslog", config, httpTimeout)
 	if err != nil {
 		return nil, err
 	}
@@ -495,23 +585,31 @@ func (c *Credentials) ConfigureSyslog(syslogIP, protocol string, port float64, t
 //
 // The function will return one of the following:
 //
-//	No change required. The Rubrik cluster is already configured with the provided DNS servers.
+//	An error matching ErrNoChangeRequired: No change required. The Rubrik cluster is already configured with the provided DNS servers.
 //
 //	The full API response for POST /internal/cluster/me/dns_nameserver
 func (c *Credentials) ConfigureDNSServers(serverIP []string, timeout ...int) (*StatusCode, error) {
+	return c.ConfigureDNSServersContext(context.Background(), serverIP, timeout...)
+}
+
+// ConfigureDNSServersContext is the same as ConfigureDNSServers with the addition of a context.Context that is used to cancel in-flight requests.
+func (c *Credentials) ConfigureDNSServersContext(ctx context.Context, serverIP []string, timeout ...int) (*StatusCode, error) {
+
+	ctx, span := c.startSpan(ctx, "ConfigureDNSServers")
+	defer span.End()
 
 	httpTimeout := httpTimeout(timeout)
 
-	currentDNSServers, err := c.Get("internal", "/cluster/me/dns_nameserver", httpTimeout)
+	currentDNSServers, err := c.GetContext(ctx, "internal", "/cluster/me/dns_nameserver", httpTimeout)
 	if err != nil {
 		return nil, err
 	}
 
 	if stringEq(serverIP, currentDNSServers.(map[string]interface{})["data"].([]interface{})) {
-		return nil, errors.New("No change required. The Rubrik cluster is already configured with the provided DNS servers")
+		return nil, newError(ErrNoChangeRequired, "No change required. The Rubrik cluster is already configured with the provided DNS servers")
 	}
 
-	apiRequest, err := c.Post("internal", "/cluster/me/dns_nameserver", serverIP, httpTimeout)
+	apiRequest, err := c.PostContext(ctx, "internal", "/cluster/me/dns_nameserver", serverIP, httpTimeout)
 	if err != nil {
 		return nil, err
 	}
@@ -531,23 +629,31 @@ func (c *Credentials) ConfigureDNSServers(serverIP []string, timeout ...int) (*S
 //
 // The function will return one of the following:
 //
-//	No change required. The Rubrik cluster is already configured with the provided DNS search domains.
+//	An error matching ErrNoChangeRequired: No change required. The Rubrik cluster is already configured with the provided DNS search domains.
 //
 //	The full API response for POST /internal/cluster/me/dns_search_domain
 func (c *Credentials) ConfigureSearchDomain(searchDomain []string, timeout ...int) (*StatusCode, error) {
+	return c.ConfigureSearchDomainContext(context.Background(), searchDomain, timeout...)
+}
+
+// ConfigureSearchDomainContext is the same as ConfigureSearchDomain with the addition of a context.Context that is used to cancel in-flight requests.
+func (c *Credentials) ConfigureSearchDomainContext(ctx context.Context, searchDomain []string, timeout ...int) (*StatusCode, error) {
+
+	ctx, span := c.startSpan(ctx, "ConfigureSearchDomain")
+	defer span.End()
 
 	httpTimeout := httpTimeout(timeout)
 
-	currentSearchDomains, err := c.Get("internal", "/cluster/me/dns_search_domain", httpTimeout)
+	currentSearchDomains, err := c.GetContext(ctx, "internal", "/cluster/me/dns_search_domain", httpTimeout)
 	if err != nil {
 		return nil, err
 	}
 
 	if stringEq(searchDomain, currentSearchDomains.(map[string]interface{})["data"].([]interface{})) {
-		return nil, errors.New("No change required. The Rubrik cluster is already configured with the provided DNS search domains")
+		return nil, newError(ErrNoChangeRequired, "No change required. The Rubrik cluster is already configured with the provided DNS search domains")
 	}
 
-	apiRequest, err := c.Post("internal", "/cluster/me/dns_search_domain", searchDomain, httpTimeout)
+	apiRequest, err := c.PostContext(ctx, "internal", "/cluster/me/dns_search_domain", searchDomain, httpTimeout)
 	if err != nil {
 		return nil, err
 	}
@@ -571,12 +677,20 @@ func (c *Credentials) ConfigureSearchDomain(searchDomain []string, timeout ...in
 //
 // The function will return one of the following:
 //
-//	No change required. The Rubrik cluster is already configured with the provided SMTP settings.
+//	An error matching ErrNoChangeRequired: No change required. The Rubrik cluster is already configured with the provided SMTP settings.
 //
 //	The full API response for POST /internal/smtp_instance
 //
 // The full API response for PATCH /smtp_instance/{smtpID}
 func (c *Credentials) ConfigureSMTPSettings(hostname, fromEmail, smtpUsername, smtpPassword, encryption string, port int, timeout ...int) (*SMTP, error) {
+	return c.ConfigureSMTPSettingsContext(context.Background(), hostname, fromEmail, smtpUsername, smtpPassword, encryption, port, timeout...)
+}
+
+// ConfigureSMTPSettingsContext is the same as ConfigureSMTPSettings with the addition of a context.Context that is used to cancel in-flight requests.
+func (c *Credentials) ConfigureSMTPSettingsContext(ctx context.Context, hostname, fromEmail, smtpUsername, smtpPassword, encryption string, port int, timeout ...int) (*SMTP, error) {
+
+	ctx, span := c.startSpan(ctx, "ConfigureSMTPSettings")
+	defer span.End()
 
 	httpTimeout := httpTimeout(timeout)
 
@@ -597,14 +711,14 @@ func (c *Credentials) ConfigureSMTPSettings(hostname, fromEmail, smtpUsername, s
 	config["smtpUsername"] = smtpUsername
 	config["fromEmailId"] = fromEmail
 
-	getSMTPSettings, err := c.Get("internal", "/smtp_instance", httpTimeout)
+	getSMTPSettings, err := c.GetContext(ctx, "internal", "/smtp_instance", httpTimeout)
 	if err != nil {
 		return nil, err
 	}
 
 	if getSMTPSettings.(map[string]interface{})["total"] == float64(0) {
 		config["smtpPassword"] = smtpPassword
-		apiRequest, err := c.Post("internal", "/smtp_instance", config, httpTimeout)
+		apiRequest, err := c.PostContext(ctx, "internal", "/smtp_instance", config, httpTimeout)
 		if err != nil {
 			return nil, err
 		}
@@ -629,10 +743,10 @@ func (c *Credentials) ConfigureSMTPSettings(hostname, fromEmail, smtpUsername, s
 
 	checkConfig := reflect.DeepEqual(config, currentSMTPSettings)
 	if checkConfig {
-		return nil, fmt.Errorf("No change required. The Rubrik cluster is already configured with the provided SMTP settings")
+		return nil, newError(ErrNoChangeRequired, "No change required. The Rubrik cluster is already configured with the provided SMTP settings")
 	}
 
-	apiRequest, err := c.Patch("internal", fmt.Sprintf("/smtp_instance/%s", smtpID), config, httpTimeout)
+	apiRequest, err := c.PatchContext(ctx, "internal", fmt.Sprintf("/smtp_instance/%s", smtpID), config, httpTimeout)
 	if err != nil {
 		return nil, err
 	}
@@ -653,10 +767,18 @@ func (c *Credentials) ConfigureSMTPSettings(hostname, fromEmail, smtpUsername, s
 //
 // The function will return one of the following:
 //
-//	No change required. The Rubrik cluster is already configured with the provided VLAN information.
+//	An error matching ErrNoChangeRequired: No change required. The Rubrik cluster is already configured with the provided VLAN information.
 //
 //	The full API response for POST /internal/cluster/me/vlan
 func (c *Credentials) ConfigureVLAN(netmask string, vlan int, ips map[string]string, timeout ...int) (*StatusCode, error) {
+	return c.ConfigureVLANContext(context.Background(), netmask, vlan, ips, timeout...)
+}
+
+// ConfigureVLANContext is the same as ConfigureVLAN with the addition of a context.Context that is used to cancel in-flight requests.
+func (c *Credentials) ConfigureVLANContext(ctx context.Context, netmask string, vlan int, ips map[string]string, timeout ...int) (*StatusCode, error) {
+
+	ctx, span := c.startSpan(ctx, "ConfigureVLAN")
+	defer span.End()
 
 	httpTimeout := httpTimeout(timeout)
 
@@ -669,7 +791,7 @@ func (c *Credentials) ConfigureVLAN(netmask string, vlan int, ips map[string]str
 	}
 	config["interfaces"] = nodeIPInterfaces
 
-	getCurrentVLANs, err := c.Get("internal", "/cluster/me/vlan", httpTimeout)
+	getCurrentVLANs, err := c.GetContext(ctx, "internal", "/cluster/me/vlan", httpTimeout)
 	if err != nil {
 		return nil, err
 	}
@@ -681,11 +803,11 @@ func (c *Credentials) ConfigureVLAN(netmask string, vlan int, ips map[string]str
 
 		checkConfig := reflect.DeepEqual(config, currentVLANs)
 		if checkConfig {
-			return nil, errors.New("No change required. The Rubrik cluster is already configured with the provided VLAN information")
+			return nil, newError(ErrNoChangeRequired, "No change required. The Rubrik cluster is already configured with the provided VLAN information")
 		}
 
 	}
-	apiRequest, err := c.Post("internal", "/cluster/me/vlan", config, httpTimeout)
+	apiRequest, err := c.PostContext(ctx, "internal", "/cluster/me/vlan", config, httpTimeout)
 	if err != nil {
 		return nil, err
 	}
@@ -705,22 +827,30 @@ func (c *Credentials) ConfigureVLAN(netmask string, vlan int, ips map[string]str
 //
 // The function will return one of the following:
 //
-//	No change required. The vCenter '{vcenterIP}' has already been added to the Rubrik cluster.
+//	An error matching ErrNoChangeRequired: No change required. The vCenter '{vcenterIP}' has already been added to the Rubrik cluster.
 //
-//	The full API response for POST /v1/VMware/vcenter
-func (c *Credentials) AddvCenter(vCenterIP, vCenterUsername, vCenterPassword string, vmLinking bool, timeout ...int) (interface{}, error) {
+//	The completed Job started by POST /v1/VMware/vcenter
+func (c *Credentials) AddvCenter(vCenterIP, vCenterUsername, vCenterPassword string, vmLinking bool, timeout ...int) (*Job, error) {
+	return c.AddvCenterContext(context.Background(), vCenterIP, vCenterUsername, vCenterPassword, vmLinking, timeout...)
+}
+
+// AddvCenterContext is the same as AddvCenter with the addition of a context.Context that is used to cancel in-flight requests and any polling.
+func (c *Credentials) AddvCenterContext(ctx context.Context, vCenterIP, vCenterUsername, vCenterPassword string, vmLinking bool, timeout ...int) (*Job, error) {
+
+	ctx, span := c.startSpan(ctx, "AddvCenter")
+	defer span.End()
 
 	httpTimeout := httpTimeout(timeout)
 
-	currentVCenter, err := c.Get("v1", "/VMware/vcenter?primary_cluster_id=local", httpTimeout)
+	currentVCenter, err := c.getAllContext(ctx, "v1", "/VMware/vcenter?primary_cluster_id=local", httpTimeout)
 	if err != nil {
-		return "", err
+		return nil, err
 	}
 
 	for _, v := range currentVCenter.(map[string]interface{})["data"].([]interface{}) {
 
 		if v.(interface{}).(map[string]interface{})["hostname"].(string) == vCenterIP {
-			return fmt.Sprintf("No change required. The vCenter '%s' has already been added to the Rubrik cluster.", vCenterIP), nil
+			return nil, newError(ErrNoChangeRequired, "No change required. The vCenter '%s' has already been added to the Rubrik cluster.", vCenterIP)
 		}
 	}
 
@@ -734,24 +864,21 @@ func (c *Credentials) AddvCenter(vCenterIP, vCenterUsername, vCenterPassword str
 		config["conflictResolutionAuthz"] = "NoConflictResolution"
 	}
 
-	apiRequest, err := c.Post("v1", "/VMware/vcenter", config, httpTimeout)
+	apiRequest, err := c.PostContext(ctx, "v1", "/VMware/vcenter", config, httpTimeout)
 	if err != nil {
-		return "", err
+		return nil, err
 	}
 
-	// Convert the API Response (map[string]interface{}) to a struct
-	var addVcenter JobStatus
-	mapErr := mapstructure.Decode(apiRequest, &addVcenter)
-	if mapErr != nil {
-		return nil, mapErr
+	job, err := c.newJob(apiRequest, httpTimeout)
+	if err != nil {
+		return nil, err
 	}
 
-	status, err := c.JobStatus(addVcenter.Links[0].Href)
-	if err != nil {
+	if _, err := job.WaitContext(ctx); err != nil {
 		return nil, err
 	}
 
-	return status, nil
+	return job, nil
 
 }
 
@@ -759,22 +886,30 @@ func (c *Credentials) AddvCenter(vCenterIP, vCenterUsername, vCenterPassword str
 //
 // The function will return one of the following:
 //
-//	No change required. The vCenter '{vcenterIP}' has already been added to the Rubrik cluster.
+//	An error matching ErrNoChangeRequired: No change required. The vCenter '{vcenterIP}' has already been added to the Rubrik cluster.
 //
-//	The full API response for POST /v1/VMware/vcenter
-func (c *Credentials) AddvCenterWithCert(vCenterIP, vCenterUsername, vCenterPassword, caCertificate string, vmLinking bool, timeout ...int) (interface{}, error) {
+//	The completed Job started by POST /v1/VMware/vcenter
+func (c *Credentials) AddvCenterWithCert(vCenterIP, vCenterUsername, vCenterPassword, caCertificate string, vmLinking bool, timeout ...int) (*Job, error) {
+	return c.AddvCenterWithCertContext(context.Background(), vCenterIP, vCenterUsername, vCenterPassword, caCertificate, vmLinking, timeout...)
+}
+
+// AddvCenterWithCertContext is the same as AddvCenterWithCert with the addition of a context.Context that is used to cancel in-flight requests and any polling.
+func (c *Credentials) AddvCenterWithCertContext(ctx context.Context, vCenterIP, vCenterUsername, vCenterPassword, caCertificate string, vmLinking bool, timeout ...int) (*Job, error) {
+
+	ctx, span := c.startSpan(ctx, "AddvCenterWithCert")
+	defer span.End()
 
 	httpTimeout := httpTimeout(timeout)
 
-	currentVCenter, err := c.Get("v1", "/VMware/vcenter?primary_cluster_id=local", httpTimeout)
+	currentVCenter, err := c.getAllContext(ctx, "v1", "/VMware/vcenter?primary_cluster_id=local", httpTimeout)
 	if err != nil {
-		return "", err
+		return nil, err
 	}
 
 	for _, v := range currentVCenter.(map[string]interface{})["data"].([]interface{}) {
 
 		if v.(interface{}).(map[string]interface{})["hostname"].(string) == vCenterIP {
-			return fmt.Sprintf("No change required. The vCenter '%s' has already been added to the Rubrik cluster.", vCenterIP), nil
+			return nil, newError(ErrNoChangeRequired, "No change required. The vCenter '%s' has already been added to the Rubrik cluster.", vCenterIP)
 		}
 	}
 
@@ -789,24 +924,21 @@ func (c *Credentials) AddvCenterWithCert(vCenterIP, vCenterUsername, vCenterPass
 	}
 	config["caCerts"] = caCertificate
 
-	apiRequest, err := c.Post("v1", "/VMware/vcenter", config, httpTimeout)
+	apiRequest, err := c.PostContext(ctx, "v1", "/VMware/vcenter", config, httpTimeout)
 	if err != nil {
-		return "", err
+		return nil, err
 	}
 
-	// Convert the API Response (map[string]interface{}) to a struct
-	var addVcenter JobStatus
-	mapErr := mapstructure.Decode(apiRequest, &addVcenter)
-	if mapErr != nil {
-		return nil, mapErr
+	job, err := c.newJob(apiRequest, httpTimeout)
+	if err != nil {
+		return nil, err
 	}
 
-	status, err := c.JobStatus(addVcenter.Links[0].Href)
-	if err != nil {
+	if _, err := job.WaitContext(ctx); err != nil {
 		return nil, err
 	}
 
-	return status, nil
+	return job, nil
 
 }
 
@@ -820,6 +952,14 @@ func (c *Credentials) AddvCenterWithCert(vCenterIP, vCenterUsername, vCenterPass
 //
 //	The full API response for POST /internal/cluster/me/bootstrap (waitForCompletion is set to false)
 func (c *Credentials) Bootstrap(clusterName, adminEmail, adminPassword, managementGateway, managementSubnetMask string, dnsSearchDomains []string, dnsNameServers []string, ntpServers map[string]interface{}, nodeConfig map[string]string, enableEncryption, waitForCompletion bool, timeout ...int) (interface{}, error) {
+	return c.BootstrapContext(context.Background(), clusterName, adminEmail, adminPassword, managementGateway, managementSubnetMask, dnsSearchDomains, dnsNameServers, ntpServers, nodeConfig, enableEncryption, waitForCompletion, timeout...)
+}
+
+// BootstrapContext is the same as Bootstrap with the addition of a context.Context that is used to cancel in-flight requests and any polling.
+func (c *Credentials) BootstrapContext(ctx context.Context, clusterName, adminEmail, adminPassword, managementGateway, managementSubnetMask string, dnsSearchDomains []string, dnsNameServers []string, ntpServers map[string]interface{}, nodeConfig map[string]string, enableEncryption, waitForCompletion bool, timeout ...int) (interface{}, error) {
+
+	ctx, span := c.startSpan(ctx, "Bootstrap")
+	defer span.End()
 
 	httpTimeout := httpTimeout(timeout)
 
@@ -876,7 +1016,7 @@ func (c *Credentials) Bootstrap(clusterName, adminEmail, adminPassword, manageme
 		config["nodeConfigs"].(map[string]interface{})[nodeName].(map[string]interface{})["managementIpConfig"].(map[string]string)["address"] = nodeIP
 	}
 
-	currentBootstrapStatus, err := c.ClusterBootstrapStatus(httpTimeout)
+	currentBootstrapStatus, err := c.ClusterBootstrapStatusContext(ctx, httpTimeout)
 	if err != nil {
 		return nil, err
 	}
@@ -884,7 +1024,7 @@ func (c *Credentials) Bootstrap(clusterName, adminEmail, adminPassword, manageme
 	if currentBootstrapStatus == true {
 		return "The provided Rubrik node is already bootstrapped.", nil
 	}
-	bootstrap, err := c.Post("internal", "/cluster/me/bootstrap", config, httpTimeout)
+	bootstrap, err := c.PostContext(ctx, "internal", "/cluster/me/bootstrap", config, httpTimeout)
 	if err != nil {
 
 		return nil, err
@@ -895,14 +1035,16 @@ func (c *Credentials) Bootstrap(clusterName, adminEmail, adminPassword, manageme
 
 		for {
 
-			bootstrapStatus, err := c.Get("internal", fmt.Sprintf("/cluster/me/bootstrap?request_id=%v", int(bootstrapRequestID)), httpTimeout)
+			bootstrapStatus, err := c.GetContext(ctx, "internal", fmt.Sprintf("/cluster/me/bootstrap?request_id=%v", int(bootstrapRequestID)), httpTimeout)
 			if err != nil {
 				return nil, err
 			}
 
 			switch bootstrapStatus.(map[string]interface{})["status"] {
 			case "IN_PROGRESS":
-				time.Sleep(30 * time.Second)
+				if err := sleepContext(ctx, 30*time.Second); err != nil {
+					return nil, err
+				}
 			case "FAILURE":
 				return nil, fmt.Errorf("%s", bootstrapStatus.(map[string]interface{})["message"])
 			case "FAILED":
@@ -929,6 +1071,14 @@ func (c *Credentials) Bootstrap(clusterName, adminEmail, adminPassword, manageme
 //
 //	The full API response for POST /internal/cluster/me/bootstrap (waitForCompletion is set to false)
 func (c *Credentials) BootstrapCcesAws(clusterName, adminEmail, adminPassword, managementGateway, managementSubnetMask string, dnsSearchDomains []string, dnsNameServers []string, ntpServers map[string]interface{}, nodeConfig map[string]string, enableEncryption bool, bucketName string, enableImmutability bool, waitForCompletion bool, timeout ...int) (interface{}, error) {
+	return c.BootstrapCcesAwsContext(context.Background(), clusterName, adminEmail, adminPassword, managementGateway, managementSubnetMask, dnsSearchDomains, dnsNameServers, ntpServers, nodeConfig, enableEncryption, bucketName, enableImmutability, waitForCompletion, timeout...)
+}
+
+// BootstrapCcesAwsContext is the same as BootstrapCcesAws with the addition of a context.Context that is used to cancel in-flight requests and any polling.
+func (c *Credentials) BootstrapCcesAwsContext(ctx context.Context, clusterName, adminEmail, adminPassword, managementGateway, managementSubnetMask string, dnsSearchDomains []string, dnsNameServers []string, ntpServers map[string]interface{}, nodeConfig map[string]string, enableEncryption bool, bucketName string, enableImmutability bool, waitForCompletion bool, timeout ...int) (interface{}, error) {
+
+	ctx, span := c.startSpan(ctx, "BootstrapCcesAws")
+	defer span.End()
 
 	httpTimeout := httpTimeout(timeout)
 
@@ -992,7 +1142,7 @@ func (c *Credentials) BootstrapCcesAws(clusterName, adminEmail, adminPassword, m
 		},
 	}
 
-	currentBootstrapStatus, err := c.ClusterBootstrapStatus(httpTimeout)
+	currentBootstrapStatus, err := c.ClusterBootstrapStatusContext(ctx, httpTimeout)
 	if err != nil {
 		return nil, err
 	}
@@ -1000,7 +1150,7 @@ func (c *Credentials) BootstrapCcesAws(clusterName, adminEmail, adminPassword, m
 	if currentBootstrapStatus == true {
 		return "The provided Rubrik node is already bootstrapped.", nil
 	}
-	bootstrap, err := c.Post("internal", "/cluster/me/bootstrap", config, httpTimeout)
+	bootstrap, err := c.PostContext(ctx, "internal", "/cluster/me/bootstrap", config, httpTimeout)
 	if err != nil {
 
 		return nil, err
@@ -1011,14 +1161,16 @@ func (c *Credentials) BootstrapCcesAws(clusterName, adminEmail, adminPassword, m
 
 		for {
 
-			bootstrapStatus, err := c.Get("internal", fmt.Sprintf("/cluster/me/bootstrap?request_id=%v", int(bootstrapRequestID)), httpTimeout)
+			bootstrapStatus, err := c.GetContext(ctx, "internal", fmt.Sprintf("/cluster/me/bootstrap?request_id=%v", int(bootstrapRequestID)), httpTimeout)
 			if err != nil {
 				return nil, err
 			}
 
 			switch bootstrapStatus.(map[string]interface{})["status"] {
 			case "IN_PROGRESS":
-				time.Sleep(30 * time.Second)
+				if err := sleepContext(ctx, 30*time.Second); err != nil {
+					return nil, err
+				}
 			case "FAILURE":
 				return nil, fmt.Errorf("%s", bootstrapStatus.(map[string]interface{})["message"])
 			case "FAILED":
@@ -1045,6 +1197,14 @@ func (c *Credentials) BootstrapCcesAws(clusterName, adminEmail, adminPassword, m
 //
 //	The full API response for POST /internal/cluster/me/bootstrap (waitForCompletion is set to false)
 func (c *Credentials) BootstrapCcesAzure(clusterName, adminEmail, adminPassword, managementGateway, managementSubnetMask string, dnsSearchDomains []string, dnsNameServers []string, ntpServers map[string]interface{}, nodeConfig map[string]string, enableEncryption bool, connectionString string, containerName string, enableImmutability bool, waitForCompletion bool, timeout ...int) (interface{}, error) {
+	return c.BootstrapCcesAzureContext(context.Background(), clusterName, adminEmail, adminPassword, managementGateway, managementSubnetMask, dnsSearchDomains, dnsNameServers, ntpServers, nodeConfig, enableEncryption, connectionString, containerName, enableImmutability, waitForCompletion, timeout...)
+}
+
+// BootstrapCcesAzureContext is the same as BootstrapCcesAzure with the addition of a context.Context that is used to cancel in-flight requests and any polling.
+func (c *Credentials) BootstrapCcesAzureContext(ctx context.Context, clusterName, adminEmail, adminPassword, managementGateway, managementSubnetMask string, dnsSearchDomains []string, dnsNameServers []string, ntpServers map[string]interface{}, nodeConfig map[string]string, enableEncryption bool, connectionString string, containerName string, enableImmutability bool, waitForCompletion bool, timeout ...int) (interface{}, error) {
+
+	ctx, span := c.startSpan(ctx, "BootstrapCcesAzure")
+	defer span.End()
 
 	httpTimeout := httpTimeout(timeout)
 
@@ -1109,7 +1269,7 @@ func (c *Credentials) BootstrapCcesAzure(clusterName, adminEmail, adminPassword,
 		},
 	}
 
-	currentBootstrapStatus, err := c.ClusterBootstrapStatus(httpTimeout)
+	currentBootstrapStatus, err := c.ClusterBootstrapStatusContext(ctx, httpTimeout)
 	if err != nil {
 		return nil, err
 	}
@@ -1117,7 +1277,7 @@ func (c *Credentials) BootstrapCcesAzure(clusterName, adminEmail, adminPassword,
 	if currentBootstrapStatus == true {
 		return "The provided Rubrik node is already bootstrapped.", nil
 	}
-	bootstrap, err := c.Post("internal", "/cluster/me/bootstrap", config, httpTimeout)
+	bootstrap, err := c.PostContext(ctx, "internal", "/cluster/me/bootstrap", config, httpTimeout)
 	if err != nil {
 
 		return nil, err
@@ -1128,14 +1288,16 @@ func (c *Credentials) BootstrapCcesAzure(clusterName, adminEmail, adminPassword,
 
 		for {
 
-			bootstrapStatus, err := c.Get("internal", fmt.Sprintf("/cluster/me/bootstrap?request_id=%v", int(bootstrapRequestID)), httpTimeout)
+			bootstrapStatus, err := c.GetContext(ctx, "internal", fmt.Sprintf("/cluster/me/bootstrap?request_id=%v", int(bootstrapRequestID)), httpTimeout)
 			if err != nil {
 				return nil, err
 			}
 
 			switch bootstrapStatus.(map[string]interface{})["status"] {
 			case "IN_PROGRESS":
-				time.Sleep(30 * time.Second)
+				if err := sleepContext(ctx, 30*time.Second); err != nil {
+					return nil, err
+				}
 			case "FAILURE":
 				return nil, fmt.Errorf("%s", bootstrapStatus.(map[string]interface{})["message"])
 			case "FAILED":
@@ -1153,8 +1315,17 @@ func (c *Credentials) BootstrapCcesAzure(clusterName, adminEmail, adminPassword,
 }
 
 // RegisterCluster submits the registration details for the specified Rubrik cluster. The username and password should
-// correspond to your Rubrik Support Portal account. The default timeout value is 160 seconds.
+// correspond to your Rubrik Support Portal account. The default timeout value is 160 seconds. An error matching
+// ErrNoChangeRequired is returned when the Rubrik cluster is already registered.
 func (c *Credentials) RegisterCluster(username, password string, timeout ...int) (interface{}, error) {
+	return c.RegisterClusterContext(context.Background(), username, password, timeout...)
+}
+
+// RegisterClusterContext is the same as RegisterCluster with the addition of a context.Context that is used to cancel in-flight requests.
+func (c *Credentials) RegisterClusterContext(ctx context.Context, username, password string, timeout ...int) (interface{}, error) {
+
+	ctx, span := c.startSpan(ctx, "RegisterCluster")
+	defer span.End()
 
 	httpTimeout := httpTimeout(timeout)
 
@@ -1163,20 +1334,20 @@ func (c *Credentials) RegisterCluster(username, password string, timeout ...int)
 		httpTimeout = 160
 	}
 
-	isRegistered, err := c.Get("internal", "/cluster/me/is_registered")
+	isRegistered, err := c.GetContext(ctx, "internal", "/cluster/me/is_registered")
 	if err != nil {
 		return nil, err
 	}
 
 	if isRegistered.(map[string]interface{})["value"] == true {
-		return "No change required. The cluster is already registered.", nil
+		return nil, newError(ErrNoChangeRequired, "No change required. The cluster is already registered.")
 	}
 
 	config := map[string]interface{}{}
 	config["username"] = username
 	config["password"] = password
 
-	register, err := c.Post("internal", "/cluster/me/register", config, httpTimeout)
+	register, err := c.PostContext(ctx, "internal", "/cluster/me/register", config, httpTimeout)
 	if err != nil {
 		return nil, err
 	}
@@ -1185,33 +1356,38 @@ func (c *Credentials) RegisterCluster(username, password string, timeout ...int)
 
 }
 
-// RefreshvCenter updates the the metadata for the specified vCenter Server and waits for the job to complete before returning the JobStatus API response.
-func (c *Credentials) RefreshvCenter(vCenterIP string, timeout ...int) (interface{}, error) {
+// RefreshvCenter updates the the metadata for the specified vCenter Server and waits for the job to complete before returning it.
+func (c *Credentials) RefreshvCenter(vCenterIP string, timeout ...int) (*Job, error) {
+	return c.RefreshvCenterContext(context.Background(), vCenterIP, timeout...)
+}
+
+// RefreshvCenterContext is the same as RefreshvCenter with the addition of a context.Context that is used to cancel in-flight requests and any polling.
+func (c *Credentials) RefreshvCenterContext(ctx context.Context, vCenterIP string, timeout ...int) (*Job, error) {
+
+	ctx, span := c.startSpan(ctx, "RefreshvCenter")
+	defer span.End()
 
 	httpTimeout := httpTimeout(timeout)
 
-	vcenterID, err := c.ObjectID(vCenterIP, "vcenter", httpTimeout)
+	vcenterID, err := c.ObjectIDContext(ctx, vCenterIP, ObjectTypeVCenter, httpTimeout)
 	if err != nil {
 		return nil, err
 	}
 
-	refresh, err := c.Post("v1", fmt.Sprintf("/VMware/vcenter/%s/refresh", vcenterID), httpTimeout)
+	refresh, err := c.PostContext(ctx, "v1", fmt.Sprintf("/VMware/vcenter/%s/refresh", vcenterID), httpTimeout)
 	if err != nil {
 		return nil, err
 	}
 
-	// Convert the API Response (map[string]interface{}) to a struct
-	var refreshJob JobStatus
-	mapErr := mapstructure.Decode(refresh, &refreshJob)
-	if mapErr != nil {
-		return nil, mapErr
+	job, err := c.newJob(refresh, httpTimeout)
+	if err != nil {
+		return nil, err
 	}
 
-	status, err := c.JobStatus(refreshJob.Links[0].Href)
-	if err != nil {
+	if _, err := job.WaitContext(ctx); err != nil {
 		return nil, err
 	}
 
-	return status, nil
+	return job, nil
 
 }
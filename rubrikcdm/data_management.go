@@ -14,8 +14,10 @@
 package rubrikcdm
 
 import (
+	"context"
 	"errors"
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/mitchellh/mapstructure"
@@ -59,201 +61,544 @@ type Cluster struct {
 	LatestEulaVersion   string `json:"latestEulaVersion"`
 }
 
+// ObjectQuery identifies an object searched by FindObject(). Only the "Type" and "Name" are required, the other fields
+// select a specific object when several objects of the same type share the "Name" (ex. two SQL Server databases named
+// "master" on different hosts).
+type ObjectQuery struct {
+	// Type is any ObjectType accepted by ObjectID() (ex. ObjectTypeMSSQLDB)
+	Type ObjectType
+	// Name is the name of the object. For "physicalHost" it is the hostname, for "ec2" the AWS Instance ID, for
+	// "fileset" the name of its Fileset Template, for "nasShare" the export point and for "replicationTarget" the name
+	// of the target Rubrik cluster.
+	Name string
+	// HostOS is the operating system of a "filesetTemplate", either Linux or Windows
+	HostOS string
+	// Hostname is the host, cluster or RAC of a "fileset", "mssqlDB", "mssqlInstance", "oracleDB", "nasShare" or "volumeGroup"
+	Hostname string
+	// Instance is the SQL Server instance of a "mssqlDB" (ex. MSSQLSERVER)
+	Instance string
+	// ShareType is the protocol of a "nasShare", either NFS or SMB
+	ShareType string
+}
+
+// objectLookup describes how the objects of a type are listed and matched by FindObject().
+type objectLookup struct {
+	apiVersion string
+	endpoint   func(query ObjectQuery) string
+	// nameField is the field of each object compared to the name of the query
+	nameField string
+	// hostnameFields are the fields of each object compared to the Hostname of the query, any of them may match
+	hostnameFields []string
+	instanceField  string
+	shareTypeField string
+}
+
+// objectLookups describes how the objects of every ObjectType are found.
+var objectLookups = map[ObjectType]objectLookup{
+	ObjectTypeVMware: {
+		apiVersion: "v1",
+		endpoint: func(query ObjectQuery) string {
+			return fmt.Sprintf("/vmware/vm?primary_cluster_id=local&is_relic=false&name=%s", query.Name)
+		},
+		nameField: "name",
+	},
+	ObjectTypeSLA: {
+		apiVersion: "v1",
+		endpoint: func(query ObjectQuery) string {
+			return fmt.Sprintf("/sla_domain?primary_cluster_id=local&name=%s", query.Name)
+		},
+		nameField: "name",
+	},
+	ObjectTypeVMwareHost: {
+		apiVersion: "v1",
+		endpoint: func(query ObjectQuery) string {
+			return "/vmware/host?primary_cluster_id=local"
+		},
+		nameField: "name",
+	},
+	ObjectTypeVMwareDatastore: {
+		apiVersion: "internal",
+		endpoint: func(query ObjectQuery) string {
+			return "/vmware/datastore"
+		},
+		nameField: "name",
+	},
+	ObjectTypePhysicalHost: {
+		apiVersion: "v1",
+		endpoint: func(query ObjectQuery) string {
+			return fmt.Sprintf("/host?primary_cluster_id=local&hostname=%s", query.Name)
+		},
+		nameField: "hostname",
+	},
+	ObjectTypeFilesetTemplate: {
+		apiVersion: "v1",
+		endpoint: func(query ObjectQuery) string {
+			return fmt.Sprintf("/fileset_template?primary_cluster_id=local&operating_system_type=%s&name=%s", query.HostOS, query.Name)
+		},
+		nameField: "name",
+	},
+	ObjectTypeManagedVolume: {
+		apiVersion: "internal",
+		endpoint: func(query ObjectQuery) string {
+			return fmt.Sprintf("/managed_volume?is_relic=false&primary_cluster_id=local&name=%s", query.Name)
+		},
+		nameField: "name",
+	},
+	ObjectTypeVCenter: {
+		apiVersion: "v1",
+		endpoint: func(query ObjectQuery) string {
+			return "/vmware/vcenter"
+		},
+		nameField: "name",
+	},
+	ObjectTypeEC2: {
+		apiVersion: "internal",
+		endpoint: func(query ObjectQuery) string {
+			return fmt.Sprintf("/aws/ec2_instance?name=%s&is_relic=false&sort_by=instanceId&sort_order=asc", query.Name)
+		},
+		nameField: "instanceId",
+	},
+	ObjectTypeAHV: {
+		apiVersion: "internal",
+		endpoint: func(query ObjectQuery) string {
+			return fmt.Sprintf("/nutanix/vm?primary_cluster_id=local&is_relic=false&name=%s", query.Name)
+		},
+		nameField: "name",
+	},
+	ObjectTypeHyperV: {
+		apiVersion: "internal",
+		endpoint: func(query ObjectQuery) string {
+			return fmt.Sprintf("/hyperv/vm?primary_cluster_id=local&is_relic=false&name=%s", query.Name)
+		},
+		nameField: "name",
+	},
+	ObjectTypeFileset: {
+		apiVersion: "v1",
+		endpoint: func(query ObjectQuery) string {
+			return fmt.Sprintf("/fileset?primary_cluster_id=local&is_relic=false&name=%s", query.Name)
+		},
+		nameField:      "name",
+		hostnameFields: []string{"hostName"},
+	},
+	ObjectTypeMSSQLDB: {
+		apiVersion: "v1",
+		endpoint: func(query ObjectQuery) string {
+			return fmt.Sprintf("/mssql/db?primary_cluster_id=local&is_relic=false&name=%s", query.Name)
+		},
+		nameField:      "name",
+		hostnameFields: []string{"rootProperties.rootName"},
+		instanceField:  "instanceName",
+	},
+	ObjectTypeMSSQLInstance: {
+		apiVersion: "v1",
+		endpoint: func(query ObjectQuery) string {
+			return "/mssql/instance?primary_cluster_id=local"
+		},
+		nameField:      "name",
+		hostnameFields: []string{"rootProperties.rootName"},
+	},
+	ObjectTypeOracleDB: {
+		apiVersion: "internal",
+		endpoint: func(query ObjectQuery) string {
+			return fmt.Sprintf("/oracle/db?primary_cluster_id=local&is_relic=false&name=%s", query.Name)
+		},
+		nameField:      "name",
+		hostnameFields: []string{"standaloneHostName", "racName"},
+	},
+	ObjectTypeNASShare: {
+		apiVersion: "internal",
+		endpoint: func(query ObjectQuery) string {
+			return "/host/share?primary_cluster_id=local"
+		},
+		nameField:      "exportPoint",
+		hostnameFields: []string{"hostname"},
+		shareTypeField: "shareType",
+	},
+	ObjectTypeVolumeGroup: {
+		apiVersion: "internal",
+		endpoint: func(query ObjectQuery) string {
+			return fmt.Sprintf("/volume_group?primary_cluster_id=local&is_relic=false&name=%s", query.Name)
+		},
+		nameField:      "name",
+		hostnameFields: []string{"hostname"},
+	},
+	ObjectTypeOrganization: {
+		apiVersion: "internal",
+		endpoint: func(query ObjectQuery) string {
+			return fmt.Sprintf("/organization?name=%s", query.Name)
+		},
+		nameField: "name",
+	},
+	ObjectTypeReplicationTarget: {
+		apiVersion: "internal",
+		endpoint: func(query ObjectQuery) string {
+			return "/replication/target"
+		},
+		nameField: "targetClusterName",
+	},
+}
+
 // ObjectID will search the Rubrik cluster for the provided "objectName" and return its ID/
 //
-// Valid "objectType" choices are:
+// Valid "objectType" choices are the ObjectType constants:
+//
+//	vmware, sla, vmwareHost, vmwareDatastore, physicalHost, filesetTemplate, fileset, managedVolume, vcenter, ec2, ahv,
+//	hyperv, mssqlDB, mssqlInstance, oracleDB, nasShare, volumeGroup, organization, and replicationTarget.
 //
-//	vmware, sla, vmwareHost, physicalHost, filesetTemplate, managedVolume, vcenter, and ec2.
+// When the "objectType" is "ec2", the objectName should correspond to the AWS Instance ID. When the "objectType" is
+// "nasShare", the objectName should correspond to the export point of the share. The "hostOS" is required for the
+// "filesetTemplate" objectType. Use FindObject() to select a specific object when several objects share the same name
+// (ex. SQL Server databases on different hosts).
 //
-// When the "objectType" is "ec2", the objectName should correspond to the AWS Instance ID.
-func (c *Credentials) ObjectID(objectName, objectType string, timeout int, hostOS ...string) (string, error) {
+// When the Client was created with WithObjectCache(), the ID is returned from the cache until its TTL expires.
+func (c *Credentials) ObjectID(objectName string, objectType ObjectType, timeout int, hostOS ...string) (string, error) {
+	return c.ObjectIDContext(context.Background(), objectName, objectType, timeout, hostOS...)
+}
+
+// ObjectIDContext is the same as ObjectID with the addition of a context.Context that is used to cancel in-flight requests.
+func (c *Credentials) ObjectIDContext(ctx context.Context, objectName string, objectType ObjectType, timeout int, hostOS ...string) (string, error) {
 
-	validObjectType := map[string]bool{
-		"vmware":          true,
-		"sla":             true,
-		"vmwareHost":      true,
-		"physicalHost":    true,
-		"filesetTemplate": true,
-		"managedVolume":   true,
-		"vcenter":         true,
-		"ec2":             true,
-		"ahv":             true,
+	ctx, span := c.startSpan(ctx, "ObjectID")
+	defer span.End()
+
+	objectType, err := checkObjectType("ObjectID", objectType)
+	if err != nil {
+		return "", err
 	}
 
-	if validObjectType[objectType] == false {
-		return "", fmt.Errorf("The 'objectType' must be 'vmware', 'sla', 'vmwareHost', 'physicalHost', 'filesetTemplate', 'managedVolume', or 'vcenter'")
+	query := ObjectQuery{Type: objectType, Name: objectName}
+	if objectType == ObjectTypeFilesetTemplate {
+		if len(hostOS) == 0 {
+			return "", errors.New("You must provide the Fileset Template OS type")
+		}
+		query.HostOS = hostOS[0]
 	}
 
-	var objectSummaryAPIVersion string
-	var objectSummaryAPIEndpoint string
-	switch objectType {
-	case "vmware":
-		objectSummaryAPIVersion = "v1"
-		objectSummaryAPIEndpoint = fmt.Sprintf("/vmware/vm?primary_cluster_id=local&is_relic=false&name=%s", objectName)
-	case "sla":
-		objectSummaryAPIVersion = "v1"
-		objectSummaryAPIEndpoint = fmt.Sprintf("/sla_domain?primary_cluster_id=local&name=%s", objectName)
-	case "vmwareHost":
-		objectSummaryAPIVersion = "v1"
-		objectSummaryAPIEndpoint = "/vmware/host?primary_cluster_id=local"
-	case "physicalHost":
-		objectSummaryAPIVersion = "v1"
-		objectSummaryAPIEndpoint = fmt.Sprintf("/host?primary_cluster_id=local&hostname=%s", objectName)
-	case "filesetTemplate":
-		var hostOperatingSystem string
-		if len(hostOS) > 0 {
-			hostOperatingSystem = hostOS[0]
-			switch hostOperatingSystem {
-			case "Linux":
-			case "Windows":
-			default:
-				return "", errors.New("The hostOS must be either 'Linux' or 'Windows'")
+	return c.findObject(ctx, query, timeout)
+}
 
-			}
-		} else if len(hostOS) == 0 {
-			return "", errors.New("You must provide the Fileset Template OS type")
+// FindObject searches the Rubrik cluster for the object that matches every non-empty field of the query and returns its
+// ID, ex:
+//
+//	dbID, err := rubrik.FindObject(rubrikcdm.ObjectQuery{Type: "mssqlDB", Name: "AdventureWorks", Hostname: "sql01.example.com", Instance: "MSSQLSERVER"})
+//
+// An error that matches ErrMultipleMatches is returned when the query matches more than one object.
+func (c *Credentials) FindObject(query ObjectQuery, timeout ...int) (string, error) {
+	return c.FindObjectContext(context.Background(), query, timeout...)
+}
+
+// FindObjectContext is the same as FindObject with the addition of a context.Context that is used to cancel in-flight requests.
+func (c *Credentials) FindObjectContext(ctx context.Context, query ObjectQuery, timeout ...int) (string, error) {
+
+	ctx, span := c.startSpan(ctx, "FindObject")
+	defer span.End()
+
+	return c.findObject(ctx, query, httpTimeout(timeout))
+}
+
+// findObject returns the ID of the object from the object cache or from the Rubrik cluster.
+func (c *Credentials) findObject(ctx context.Context, query ObjectQuery, timeout int) (string, error) {
+
+	objectType, err := checkObjectType("ObjectID", query.Type)
+	if err != nil {
+		return "", err
+	}
+	query.Type = objectType
+
+	cache := c.objectCache()
+	if cache != nil {
+		if objectID, ok := cache.get(query); ok {
+			return objectID, nil
 		}
-		objectSummaryAPIVersion = "v1"
-		objectSummaryAPIEndpoint = fmt.Sprintf("/fileset_template?primary_cluster_id=local&operating_system_type=%s&name=%s", hostOperatingSystem, objectName)
-	case "managedVolume":
-		objectSummaryAPIVersion = "internal"
-		objectSummaryAPIEndpoint = fmt.Sprintf("/managed_volume?is_relic=false&primary_cluster_id=local&name=%s", objectName)
-	case "vcenter":
-		objectSummaryAPIVersion = "v1"
-		objectSummaryAPIEndpoint = "/vmware/vcenter"
-	case "ec2":
-		objectSummaryAPIVersion = "internal"
-		objectSummaryAPIEndpoint = fmt.Sprintf("/aws/ec2_instance?name=%s&is_relic=false&sort_by=instanceId&sort_order=asc", objectName)
-	case "ahv":
-		objectSummaryAPIVersion = "internal"
-		objectSummaryAPIEndpoint = fmt.Sprintf("/nutanix/vm?primary_cluster_id=local&is_relic=false&name=%s", objectName)
-	}
-
-	apiRequest, err := c.Get(objectSummaryAPIVersion, objectSummaryAPIEndpoint, timeout)
+	}
+
+	objectID, err := c.lookupObject(ctx, query, timeout)
+	if err != nil {
+		return "", err
+	}
+
+	if cache != nil {
+		cache.set(query, objectID)
+	}
+
+	return objectID, nil
+}
+
+// lookupObject searches the Rubrik cluster for the object without using the object cache.
+func (c *Credentials) lookupObject(ctx context.Context, query ObjectQuery, timeout int) (string, error) {
+
+	lookup := objectLookups[query.Type]
+
+	if query.Type == ObjectTypeFilesetTemplate && query.HostOS != "Linux" && query.HostOS != "Windows" {
+		return "", errors.New("The hostOS must be either 'Linux' or 'Windows'")
+	}
+
+	if query.Hostname != "" && len(lookup.hostnameFields) == 0 {
+		return "", fmt.Errorf("The 'Hostname' can not be used to find a %s object", query.Type)
+	}
+	if query.Instance != "" && lookup.instanceField == "" {
+		return "", fmt.Errorf("The 'Instance' can not be used to find a %s object", query.Type)
+	}
+	if query.ShareType != "" && lookup.shareTypeField == "" {
+		return "", fmt.Errorf("The 'ShareType' can not be used to find a %s object", query.Type)
+	}
+
+	apiRequest, err := c.getAllContext(ctx, lookup.apiVersion, lookup.endpoint(query), timeout)
 	if err != nil {
 		return "", err
 	}
-	if apiRequest.(map[string]interface{})["total"] == 0 {
-		return "", fmt.Errorf("The %s object '%s' was not found on the Rubrik cluster", objectType, objectName)
-	} else if apiRequest.(map[string]interface{})["total"].(float64) > 0 {
-		objectIDs := make([]string, 0)
-		// # Define the "object name" to search for
-		var nameValue string
-		if objectType == "physicalHost" {
-			nameValue = "hostname"
-		} else if objectType == "ec2" {
-			nameValue = "instanceId"
-		} else {
-			nameValue = "name"
+
+	objectIDs := make([]string, 0)
+	for _, v := range apiRequest.(map[string]interface{})["data"].([]interface{}) {
+		object, ok := v.(map[string]interface{})
+		if ok == false || lookup.matches(object, query) == false {
+			continue
 		}
+		objectIDs = append(objectIDs, objectField(object, "id"))
+	}
+
+	if len(objectIDs) > 1 {
+		return "", newError(ErrMultipleMatches, "Multiple %s objects named '%s' were found on the Rubrik cluster. Unable to return a specific object id", query.Type, query.Name)
+	} else if len(objectIDs) == 0 {
+		return "", newError(ErrNotFound, "The %s object '%s' was not found on the Rubrik cluster", query.Type, query.Name)
+	}
 
-		for _, v := range apiRequest.(map[string]interface{})["data"].([]interface{}) {
-			if v.(interface{}).(map[string]interface{})[nameValue].(string) == objectName {
-				objectIDs = append(objectIDs, v.(interface{}).(map[string]interface{})["id"].(string))
+	return objectIDs[0], nil
+}
+
+// matches returns true when the object matches every non-empty field of the query.
+func (lookup objectLookup) matches(object map[string]interface{}, query ObjectQuery) bool {
+
+	if objectField(object, lookup.nameField) != query.Name {
+		return false
+	}
+
+	if query.Hostname != "" {
+		hostnameMatch := false
+		for _, field := range lookup.hostnameFields {
+			if strings.EqualFold(objectField(object, field), query.Hostname) {
+				hostnameMatch = true
 			}
 		}
+		if hostnameMatch == false {
+			return false
+		}
+	}
 
-		if len(objectIDs) > 1 {
-			return "", fmt.Errorf("Multiple %s objects named '%s' were found on the Rubrik cluster. Unable to return a specific object id", objectType, objectName)
-		} else if len(objectIDs) == 0 {
-			return "", fmt.Errorf("The %s object '%s' was not found on the Rubrik cluster", objectType, objectName)
-		} else {
-			return objectIDs[0], nil
+	if query.Instance != "" && strings.EqualFold(objectField(object, lookup.instanceField), query.Instance) == false {
+		return false
+	}
+
+	if query.ShareType != "" && strings.EqualFold(objectField(object, lookup.shareTypeField), query.ShareType) == false {
+		return false
+	}
+
+	return true
+}
+
+// objectField returns the string value of a field of an object in the API response, using '.' to access nested
+// fields (ex. rootProperties.rootName), or an empty string when the field does not exist.
+func objectField(object map[string]interface{}, field string) string {
+
+	var value interface{} = object
+	for _, key := range strings.Split(field, ".") {
+		nested, ok := value.(map[string]interface{})
+		if ok == false {
+			return ""
 		}
+		value = nested[key]
+	}
+
+	if value, ok := value.(string); ok {
+		return value
 	}
+	return ""
+}
 
-	return "", fmt.Errorf("The %s object '%s' was not found on the Rubrik cluster", objectType, objectName)
+// slaAssignment is the endpoint that returns the SLA Domain currently assigned to an object and how the object is
+// described in the messages of AssignSLA().
+type slaAssignment struct {
+	apiVersion  string
+	endpoint    string
+	description string
+}
 
+// slaAssignments are the object types that can be assigned to an SLA Domain through AssignSLA().
+var slaAssignments = map[ObjectType]slaAssignment{
+	ObjectTypeVMware:        {apiVersion: "v1", endpoint: "/vmware/vm/%s", description: "vSphere VM"},
+	ObjectTypeAHV:           {apiVersion: "internal", endpoint: "/nutanix/vm/%s", description: "AHV VM"},
+	ObjectTypeHyperV:        {apiVersion: "internal", endpoint: "/hyperv/vm/%s", description: "Hyper-V VM"},
+	ObjectTypeFileset:       {apiVersion: "v1", endpoint: "/fileset/%s", description: "fileset"},
+	ObjectTypeMSSQLDB:       {apiVersion: "v1", endpoint: "/mssql/db/%s", description: "SQL Server database"},
+	ObjectTypeMSSQLInstance: {apiVersion: "v1", endpoint: "/mssql/instance/%s", description: "SQL Server instance"},
+	ObjectTypeOracleDB:      {apiVersion: "internal", endpoint: "/oracle/db/%s", description: "Oracle database"},
+	ObjectTypeManagedVolume: {apiVersion: "internal", endpoint: "/managed_volume/%s", description: "Managed Volume"},
+	ObjectTypeNASShare:      {apiVersion: "internal", endpoint: "/host/share/%s", description: "NAS share"},
+	ObjectTypeVolumeGroup:   {apiVersion: "internal", endpoint: "/volume_group/%s", description: "volume group"},
+	ObjectTypeEC2:           {apiVersion: "internal", endpoint: "/aws/ec2_instance/%s", description: "EC2 instance"},
 }
 
-// AssignSLA adds the "objectName" to the "slaName". vmware and ahv are the only supported "objectType". To exclude the object from all SLA assignments
-// use "do not protect" as the "slaName". To assign the selected object to the SLA of the next higher level object, use "clear" as the "slaName".
+// AssignSLA adds the "objectName" to the "slaName". To exclude the object from all SLA assignments use "do not protect" as the "slaName". To assign
+// the selected object to the SLA of the next higher level object, use "clear" as the "slaName".
+//
+// Valid "objectType" choices are:
+//
+//	vmware, ahv, hyperv, fileset, mssqlDB, mssqlInstance, oracleDB, managedVolume, nasShare, volumeGroup, and ec2.
+//
+// Physical hosts are protected through their filesets. Use AssignSLABulk() to assign an object whose name is not unique
+// (ex. a fileset, which is named after its Fileset Template) through an ObjectQuery.
 //
 // The function will return one of the following:
 //
-//	No change required. The vSphere VM '{objectName}' is already assigned to the '{slaName}' SLA Domain.
+//	An error matching ErrNoChangeRequired: No change required. The vSphere VM '{objectName}' is already assigned to the '{slaName}' SLA Domain.
 //
 //	The full API response for POST /internal/sla_domain/{slaID}/assign.
-func (c *Credentials) AssignSLA(objectName, objectType, slaName string, timeout ...int) (*StatusCode, error) {
+func (c *Credentials) AssignSLA(objectName string, objectType ObjectType, slaName string, timeout ...int) (*StatusCode, error) {
+	return c.AssignSLAContext(context.Background(), objectName, objectType, slaName, timeout...)
+}
+
+// AssignSLAContext is the same as AssignSLA with the addition of a context.Context that is used to cancel in-flight requests.
+func (c *Credentials) AssignSLAContext(ctx context.Context, objectName string, objectType ObjectType, slaName string, timeout ...int) (*StatusCode, error) {
+
+	ctx, span := c.startSpan(ctx, "AssignSLA")
+	defer span.End()
 
 	httpTimeout := httpTimeout(timeout)
 
-	validObjectType := map[string]bool{
-		"vmware": true,
-		"ahv":    true,
+	objectType, err := checkObjectType("AssignSLA", objectType)
+	if err != nil {
+		return nil, err
 	}
 
-	if validObjectType[objectType] == false {
-		return nil, fmt.Errorf("The 'objectType' must be 'vmware' or 'ahv'.")
+	slaID, err := c.assignmentSLAID(ctx, slaName, httpTimeout)
+	if err != nil {
+		return nil, err
 	}
 
-	var slaID string
-	var err error
-	switch slaName {
-	case "do not protect":
-		slaID = "UNPROTECTED"
-	case "clear":
-		slaID = "INHERIT"
-	default:
-		slaID, err = c.ObjectID(slaName, "sla", httpTimeout)
+	objectID, err := c.ObjectIDContext(ctx, objectName, objectType, httpTimeout)
+	if err != nil {
+		return nil, err
+	}
+
+	assigned, err := c.slaAssigned(ctx, objectType, objectID, slaID, httpTimeout)
+	if err != nil {
+		return nil, err
+	}
+
+	if assigned {
+		return nil, newError(ErrNoChangeRequired, "No change required. The %s '%s' is already assigned to the '%s' SLA Domain", slaAssignments[objectType].description, objectName, slaName)
+	}
+
+	return c.assignSLA(ctx, slaID, []string{objectID}, httpTimeout)
+}
+
+// AssignSLABulk adds every object to the "slaName" through a single POST /internal/sla_domain/{slaID}/assign request.
+// The objects are found through FindObject() and support the same "Type" as the "objectType" of AssignSLA(). The
+// objects already assigned to the SLA Domain are left out of the request. Use "do not protect" or "clear" as the
+// "slaName" in the same way as AssignSLA().
+//
+// The function will return one of the following:
+//
+//	An error matching ErrNoChangeRequired: No change required. Every object is already assigned to the '{slaName}' SLA Domain.
+//
+//	The full API response for POST /internal/sla_domain/{slaID}/assign.
+func (c *Credentials) AssignSLABulk(objects []ObjectQuery, slaName string, timeout ...int) (*StatusCode, error) {
+	return c.AssignSLABulkContext(context.Background(), objects, slaName, timeout...)
+}
+
+// AssignSLABulkContext is the same as AssignSLABulk with the addition of a context.Context that is used to cancel in-flight requests.
+func (c *Credentials) AssignSLABulkContext(ctx context.Context, objects []ObjectQuery, slaName string, timeout ...int) (*StatusCode, error) {
+
+	ctx, span := c.startSpan(ctx, "AssignSLABulk")
+	defer span.End()
+
+	httpTimeout := httpTimeout(timeout)
+
+	if len(objects) == 0 {
+		return nil, errors.New("At least one object must be provided")
+	}
+
+	// Validate every object type before sending any request, without modifying the caller's objects
+	queries := make([]ObjectQuery, len(objects))
+	for i, object := range objects {
+		objectType, err := checkObjectType("AssignSLA", object.Type)
 		if err != nil {
 			return nil, err
 		}
+		queries[i] = object
+		queries[i].Type = objectType
 	}
 
-	config := map[string]interface{}{}
-	switch objectType {
-	case "vmware":
-		vmID, err := c.ObjectID(objectName, "vmware", httpTimeout)
+	slaID, err := c.assignmentSLAID(ctx, slaName, httpTimeout)
+	if err != nil {
+		return nil, err
+	}
+
+	managedIDs := []string{}
+	for _, object := range queries {
+		objectID, err := c.findObject(ctx, object, httpTimeout)
 		if err != nil {
 			return nil, err
 		}
 
-		vmSummary, err := c.Get("v1", fmt.Sprintf("/vmware/vm/%s", vmID), httpTimeout)
+		assigned, err := c.slaAssigned(ctx, object.Type, objectID, slaID, httpTimeout)
 		if err != nil {
 			return nil, err
 		}
 
-		var currentSLAID string
-		switch slaID {
-		case "INHERIT":
-			currentSLAID = vmSummary.(map[string]interface{})["configuredSlaDomainId"].(string)
-		default:
-			currentSLAID = vmSummary.(map[string]interface{})["effectiveSlaDomainId"].(string)
+		if assigned == false {
+			managedIDs = append(managedIDs, objectID)
 		}
+	}
 
-		if slaID == currentSLAID {
-			return nil, fmt.Errorf("No change required. The vSphere VM '%s' is already assigned to the '%s' SLA Domain", objectName, slaName)
-		}
+	if len(managedIDs) == 0 {
+		return nil, newError(ErrNoChangeRequired, "No change required. Every object is already assigned to the '%s' SLA Domain", slaName)
+	}
 
-		config["managedIds"] = []string{vmID}
-	case "ahv":
-		vmID, err := c.ObjectID(objectName, "ahv", httpTimeout)
-		if err != nil {
-			return nil, err
-		}
+	return c.assignSLA(ctx, slaID, managedIDs, httpTimeout)
+}
 
-		vmSummary, err := c.Get("internal", fmt.Sprintf("/nutanix/vm/%s", vmID), httpTimeout)
-		if err != nil {
-			return nil, err
-		}
+// assignmentSLAID returns the SLA Domain ID used to assign objects to the "slaName", including the special
+// "do not protect" and "clear" names.
+func (c *Credentials) assignmentSLAID(ctx context.Context, slaName string, timeout int) (string, error) {
+	switch slaName {
+	case "do not protect":
+		return "UNPROTECTED", nil
+	case "clear":
+		return "INHERIT", nil
+	}
+	return c.ObjectIDContext(ctx, slaName, ObjectTypeSLA, timeout)
+}
 
-		var currentSLAID string
-		switch slaID {
-		case "INHERIT":
-			currentSLAID = vmSummary.(map[string]interface{})["configuredSlaDomainId"].(string)
-		default:
-			currentSLAID = vmSummary.(map[string]interface{})["effectiveSlaDomainId"].(string)
-		}
+// slaAssigned returns true when the object is already assigned to the SLA Domain. An object inherits its SLA Domain
+// when its configured SLA Domain is INHERIT, otherwise its effective SLA Domain is compared.
+func (c *Credentials) slaAssigned(ctx context.Context, objectType ObjectType, objectID, slaID string, timeout int) (bool, error) {
 
-		if slaID == currentSLAID {
-			return nil, fmt.Errorf("No change required. The AHV VM '%s' is already assigned to the '%s' SLA Domain", objectName, slaName)
-		}
+	assignment := slaAssignments[objectType]
 
-		config["managedIds"] = []string{vmID}
+	summary, err := c.GetContext(ctx, assignment.apiVersion, fmt.Sprintf(assignment.endpoint, objectID), timeout)
+	if err != nil {
+		return false, err
+	}
+
+	object, ok := summary.(map[string]interface{})
+	if ok == false {
+		return false, fmt.Errorf("The API response of the %s '%s' is not an object", assignment.description, objectID)
+	}
+
+	if slaID == "INHERIT" {
+		return objectField(object, "configuredSlaDomainId") == slaID, nil
 	}
+	return objectField(object, "effectiveSlaDomainId") == slaID, nil
+}
 
-	apiRequest, err := c.Post("internal", fmt.Sprintf("/sla_domain/%s/assign", slaID), config, httpTimeout)
+// assignSLA assigns the objects to the SLA Domain.
+func (c *Credentials) assignSLA(ctx context.Context, slaID string, managedIDs []string, timeout int) (*StatusCode, error) {
+
+	config := map[string]interface{}{}
+	config["managedIds"] = managedIDs
+
+	apiRequest, err := c.PostContext(ctx, "internal", fmt.Sprintf("/sla_domain/%s/assign", slaID), config, timeout)
 	if err != nil {
 		return nil, err
 	}
@@ -273,30 +618,38 @@ func (c *Credentials) AssignSLA(objectName, objectType, slaName string, timeout
 //
 // The function will return one of the following:
 //
-//	No change required. The Managed Volume '{name}' is already in a writeable state.
+//	An error matching ErrNoChangeRequired: No change required. The Managed Volume '{name}' is already in a writeable state.
 //
 //	The full API response for POST /internal/managed_volume/{managedVolumeID}/begin_snapshot
 func (c *Credentials) BeginManagedVolumeSnapshot(name string, timeout ...int) (*StatusCode, error) {
+	return c.BeginManagedVolumeSnapshotContext(context.Background(), name, timeout...)
+}
+
+// BeginManagedVolumeSnapshotContext is the same as BeginManagedVolumeSnapshot with the addition of a context.Context that is used to cancel in-flight requests.
+func (c *Credentials) BeginManagedVolumeSnapshotContext(ctx context.Context, name string, timeout ...int) (*StatusCode, error) {
+
+	ctx, span := c.startSpan(ctx, "BeginManagedVolumeSnapshot")
+	defer span.End()
 
 	httpTimeout := httpTimeout(timeout)
 
-	managedVolumeID, err := c.ObjectID(name, "managedVolume", httpTimeout)
+	managedVolumeID, err := c.ObjectIDContext(ctx, name, ObjectTypeManagedVolume, httpTimeout)
 	if err != nil {
 		return nil, err
 	}
 
-	managedVolumeSummary, err := c.Get("internal", fmt.Sprintf("/managed_volume/%s", managedVolumeID), httpTimeout)
+	managedVolumeSummary, err := c.GetContext(ctx, "internal", fmt.Sprintf("/managed_volume/%s", managedVolumeID), httpTimeout)
 	if err != nil {
 		return nil, err
 	}
 
 	if managedVolumeSummary.(map[string]interface{})["isWritable"].(bool) {
-		return nil, fmt.Errorf("No change required. The Managed Volume '%s' is already in a writeable state", name)
+		return nil, newError(ErrNoChangeRequired, "No change required. The Managed Volume '%s' is already in a writeable state", name)
 	}
 
 	config := map[string]string{}
 
-	apiRequest, err := c.Post("internal", fmt.Sprintf("/managed_volume/%s/begin_snapshot", managedVolumeID), config, httpTimeout)
+	apiRequest, err := c.PostContext(ctx, "internal", fmt.Sprintf("/managed_volume/%s/begin_snapshot", managedVolumeID), config, httpTimeout)
 	if err != nil {
 		return nil, err
 	}
@@ -315,25 +668,33 @@ func (c *Credentials) BeginManagedVolumeSnapshot(name string, timeout ...int) (*
 //
 // The function will return one of the following:
 //
-//	No change required. The Managed Volume '{name}' is already in a read-only state.
+//	An error matching ErrNoChangeRequired: No change required. The Managed Volume '{name}' is already in a read-only state.
 //
 //	The full API response for POST /internal/managed_volume/{managedVolumeID}/end_snapshot
 func (c *Credentials) EndManagedVolumeSnapshot(name, slaName string, timeout ...int) (*EndManagedVolumeSnapshot, error) {
+	return c.EndManagedVolumeSnapshotContext(context.Background(), name, slaName, timeout...)
+}
+
+// EndManagedVolumeSnapshotContext is the same as EndManagedVolumeSnapshot with the addition of a context.Context that is used to cancel in-flight requests.
+func (c *Credentials) EndManagedVolumeSnapshotContext(ctx context.Context, name, slaName string, timeout ...int) (*EndManagedVolumeSnapshot, error) {
+
+	ctx, span := c.startSpan(ctx, "EndManagedVolumeSnapshot")
+	defer span.End()
 
 	httpTimeout := httpTimeout(timeout)
 
-	managedVolumeID, err := c.ObjectID(name, "managedVolume", httpTimeout)
+	managedVolumeID, err := c.ObjectIDContext(ctx, name, ObjectTypeManagedVolume, httpTimeout)
 	if err != nil {
 		return nil, err
 	}
 
-	managedVolumeSummary, err := c.Get("internal", fmt.Sprintf("/managed_volume/%s", managedVolumeID), httpTimeout)
+	managedVolumeSummary, err := c.GetContext(ctx, "internal", fmt.Sprintf("/managed_volume/%s", managedVolumeID), httpTimeout)
 	if err != nil {
 		return nil, err
 	}
 
 	if managedVolumeSummary.(map[string]interface{})["isWritable"].(bool) == false {
-		return nil, fmt.Errorf("No change required. The Managed Volume '%s' is already in a read-only state", name)
+		return nil, newError(ErrNoChangeRequired, "No change required. The Managed Volume '%s' is already in a read-only state", name)
 	}
 
 	var slaID string
@@ -341,7 +702,7 @@ func (c *Credentials) EndManagedVolumeSnapshot(name, slaName string, timeout ...
 	switch slaName {
 	case "current":
 	default:
-		slaID, err = c.ObjectID(slaName, "sla", httpTimeout)
+		slaID, err = c.ObjectIDContext(ctx, slaName, ObjectTypeSLA, httpTimeout)
 		if err != nil {
 			return nil, err
 		}
@@ -349,7 +710,7 @@ func (c *Credentials) EndManagedVolumeSnapshot(name, slaName string, timeout ...
 		config["retentionConfig"].(map[string]interface{})["slaId"] = slaID
 	}
 
-	apiRequest, err := c.Post("internal", fmt.Sprintf("/managed_volume/%s/end_snapshot", managedVolumeID), config, httpTimeout)
+	apiRequest, err := c.PostContext(ctx, "internal", fmt.Sprintf("/managed_volume/%s/end_snapshot", managedVolumeID), config, httpTimeout)
 	if err != nil {
 		return nil, err
 	}
@@ -366,26 +727,31 @@ func (c *Credentials) EndManagedVolumeSnapshot(name, slaName string, timeout ...
 }
 
 // GetSLAObjects returns the name and ID of a specific object type.
-func (c *Credentials) GetSLAObjects(slaName, objectType string, timeout ...int) (interface{}, error) {
+func (c *Credentials) GetSLAObjects(slaName string, objectType ObjectType, timeout ...int) (interface{}, error) {
+	return c.GetSLAObjectsContext(context.Background(), slaName, objectType, timeout...)
+}
 
-	httpTimeout := httpTimeout(timeout)
+// GetSLAObjectsContext is the same as GetSLAObjects with the addition of a context.Context that is used to cancel in-flight requests.
+func (c *Credentials) GetSLAObjectsContext(ctx context.Context, slaName string, objectType ObjectType, timeout ...int) (interface{}, error) {
 
-	validObjectType := map[string]bool{
-		"vmware": true,
-	}
+	ctx, span := c.startSpan(ctx, "GetSLAObjects")
+	defer span.End()
+
+	httpTimeout := httpTimeout(timeout)
 
-	if validObjectType[objectType] == false {
-		return nil, fmt.Errorf("The 'objectType' must be 'vmware'")
+	objectType, err := checkObjectType("GetSLAObjects", objectType)
+	if err != nil {
+		return nil, err
 	}
 
 	switch objectType {
-	case "vmware":
-		slaID, err := c.ObjectID(slaName, "sla", httpTimeout)
+	case ObjectTypeVMware:
+		slaID, err := c.ObjectIDContext(ctx, slaName, ObjectTypeSLA, httpTimeout)
 		if err != nil {
 			return nil, err
 		}
 
-		allVMinSLA, err := c.Get("v1", fmt.Sprintf("/vmware/vm?effective_sla_domain_id=%s&is_relic=false", slaID), httpTimeout)
+		allVMinSLA, err := c.getAllContext(ctx, "v1", fmt.Sprintf("/vmware/vm?effective_sla_domain_id=%s&is_relic=false", slaID), httpTimeout)
 		if err != nil {
 			return nil, err
 		}
@@ -410,10 +776,18 @@ func (c *Credentials) GetSLAObjects(slaName, objectType string, timeout ...int)
 //
 // The function will return one of the following:
 //
-//	No change required. The '{objectName}' '{objectType}' is already paused.
+//	An error matching ErrNoChangeRequired: No change required. The '{objectName}' '{objectType}' is already paused.
 //
 //	The full API response for POST /internal/vmware/vm/{vmID}
-func (c *Credentials) PauseSnapshot(objectName, objectType string, timeout ...int) (interface{}, error) {
+func (c *Credentials) PauseSnapshot(objectName string, objectType ObjectType, timeout ...int) (interface{}, error) {
+	return c.PauseSnapshotContext(context.Background(), objectName, objectType, timeout...)
+}
+
+// PauseSnapshotContext is the same as PauseSnapshot with the addition of a context.Context that is used to cancel in-flight requests.
+func (c *Credentials) PauseSnapshotContext(ctx context.Context, objectName string, objectType ObjectType, timeout ...int) (interface{}, error) {
+
+	ctx, span := c.startSpan(ctx, "PauseSnapshot")
+	defer span.End()
 
 	httpTimeout := httpTimeout(timeout)
 
@@ -422,34 +796,31 @@ func (c *Credentials) PauseSnapshot(objectName, objectType string, timeout ...in
 		httpTimeout = 180
 	}
 
-	validObjectType := map[string]bool{
-		"vmware": true,
-	}
-
-	if validObjectType[objectType] == false {
-		return nil, fmt.Errorf("The 'objectType' must be 'vmware'")
+	objectType, err := checkObjectType("PauseSnapshot", objectType)
+	if err != nil {
+		return nil, err
 	}
 
 	switch objectType {
-	case "vmware":
-		vmID, err := c.ObjectID(objectName, "vmware", httpTimeout)
+	case ObjectTypeVMware:
+		vmID, err := c.ObjectIDContext(ctx, objectName, ObjectTypeVMware, httpTimeout)
 		if err != nil {
 			return nil, err
 		}
 
-		vmSummary, err := c.Get("v1", fmt.Sprintf("/vmware/vm/%s", vmID), httpTimeout)
+		vmSummary, err := c.GetContext(ctx, "v1", fmt.Sprintf("/vmware/vm/%s", vmID), httpTimeout)
 		if err != nil {
 			return nil, err
 		}
 
 		if vmSummary.(map[string]interface{})["blackoutWindowStatus"].(map[string]interface{})["isSnappableBlackoutActive"].(bool) {
-			return fmt.Sprintf("No change required. The '%s' '%s' is already paused.", objectName, objectType), nil
+			return nil, newError(ErrNoChangeRequired, "No change required. The '%s' '%s' is already paused.", objectName, objectType)
 		}
 
 		config := map[string]bool{}
 		config["isVmPaused"] = true
 
-		apiRequest, err := c.Patch("v1", fmt.Sprintf("/vmware/vm/%s", vmID), config, httpTimeout)
+		apiRequest, err := c.PatchContext(ctx, "v1", fmt.Sprintf("/vmware/vm/%s", vmID), config, httpTimeout)
 		if err != nil {
 			return nil, err
 		}
@@ -465,10 +836,18 @@ func (c *Credentials) PauseSnapshot(objectName, objectType string, timeout ...in
 //
 // The function will return one of the following:
 //
-//	No change required. The '{objectName}' '{objectType}' is currently not paused.
+//	An error matching ErrNoChangeRequired: No change required. The '{objectName}' '{objectType}' is currently not paused.
 //
 //	The full API response for POST /internal/vmware/vm/{vmID}
-func (c *Credentials) ResumeSnapshot(objectName, objectType string, timeout ...int) (interface{}, error) {
+func (c *Credentials) ResumeSnapshot(objectName string, objectType ObjectType, timeout ...int) (interface{}, error) {
+	return c.ResumeSnapshotContext(context.Background(), objectName, objectType, timeout...)
+}
+
+// ResumeSnapshotContext is the same as ResumeSnapshot with the addition of a context.Context that is used to cancel in-flight requests.
+func (c *Credentials) ResumeSnapshotContext(ctx context.Context, objectName string, objectType ObjectType, timeout ...int) (interface{}, error) {
+
+	ctx, span := c.startSpan(ctx, "ResumeSnapshot")
+	defer span.End()
 
 	httpTimeout := httpTimeout(timeout)
 
@@ -477,34 +856,31 @@ func (c *Credentials) ResumeSnapshot(objectName, objectType string, timeout ...i
 		httpTimeout = 180
 	}
 
-	validObjectType := map[string]bool{
-		"vmware": true,
-	}
-
-	if validObjectType[objectType] == false {
-		return nil, fmt.Errorf("The 'objectType' must be 'vmware'")
+	objectType, err := checkObjectType("ResumeSnapshot", objectType)
+	if err != nil {
+		return nil, err
 	}
 
 	switch objectType {
-	case "vmware":
-		vmID, err := c.ObjectID(objectName, "vmware", httpTimeout)
+	case ObjectTypeVMware:
+		vmID, err := c.ObjectIDContext(ctx, objectName, ObjectTypeVMware, httpTimeout)
 		if err != nil {
 			return nil, err
 		}
 
-		vmSummary, err := c.Get("v1", fmt.Sprintf("/vmware/vm/%s", vmID), httpTimeout)
+		vmSummary, err := c.GetContext(ctx, "v1", fmt.Sprintf("/vmware/vm/%s", vmID), httpTimeout)
 		if err != nil {
 			return nil, err
 		}
 
 		if vmSummary.(map[string]interface{})["blackoutWindowStatus"].(map[string]interface{})["isSnappableBlackoutActive"].(bool) == false {
-			return fmt.Sprintf("No change required. The '%s' '%s' is currently not paused.", objectName, objectType), nil
+			return nil, newError(ErrNoChangeRequired, "No change required. The '%s' '%s' is currently not paused.", objectName, objectType)
 		}
 
 		config := map[string]bool{}
 		config["isVmPaused"] = false
 
-		apiRequest, err := c.Patch("v1", fmt.Sprintf("/vmware/vm/%s", vmID), config, httpTimeout)
+		apiRequest, err := c.PatchContext(ctx, "v1", fmt.Sprintf("/vmware/vm/%s", vmID), config, httpTimeout)
 		if err != nil {
 			return nil, err
 		}
@@ -521,8 +897,16 @@ func (c *Credentials) ResumeSnapshot(objectName, objectType string, timeout ...i
 //
 // The function will return:
 //
-//	The job status URL for the on-demand Snapshot
-func (c *Credentials) OnDemandSnapshotVM(objectName, objectType, slaName string, timeout ...int) (string, error) {
+//	A Job that monitors the on-demand Snapshot. Use Wait() to wait for the Snapshot to complete
+func (c *Credentials) OnDemandSnapshotVM(objectName string, objectType ObjectType, slaName string, timeout ...int) (*Job, error) {
+	return c.OnDemandSnapshotVMContext(context.Background(), objectName, objectType, slaName, timeout...)
+}
+
+// OnDemandSnapshotVMContext is the same as OnDemandSnapshotVM with the addition of a context.Context that is used to cancel in-flight requests.
+func (c *Credentials) OnDemandSnapshotVMContext(ctx context.Context, objectName string, objectType ObjectType, slaName string, timeout ...int) (*Job, error) {
+
+	ctx, span := c.startSpan(ctx, "OnDemandSnapshotVM")
+	defer span.End()
 
 	httpTimeout := httpTimeout(timeout)
 
@@ -531,48 +915,45 @@ func (c *Credentials) OnDemandSnapshotVM(objectName, objectType, slaName string,
 		httpTimeout = 180
 	}
 
-	validObjectType := map[string]bool{
-		"vmware": true,
-	}
-
-	if validObjectType[objectType] == false {
-		return "", fmt.Errorf("The 'objectType' must be 'vmware'")
+	objectType, err := checkObjectType("OnDemandSnapshotVM", objectType)
+	if err != nil {
+		return nil, err
 	}
 
 	switch objectType {
-	case "vmware":
-		vmID, err := c.ObjectID(objectName, "vmware", httpTimeout)
+	case ObjectTypeVMware:
+		vmID, err := c.ObjectIDContext(ctx, objectName, ObjectTypeVMware, httpTimeout)
 		if err != nil {
-			return "", err
+			return nil, err
 		}
 
 		var slaID interface{}
 		switch slaName {
 		case "current":
-			slaID, err = c.Get("v1", fmt.Sprintf("/vmware/vm/%s", vmID))
+			slaID, err = c.GetContext(ctx, "v1", fmt.Sprintf("/vmware/vm/%s", vmID))
 			if err != nil {
-				return "", err
+				return nil, err
 			}
 		default:
-			slaID, err = c.ObjectID(slaName, "sla", httpTimeout)
+			slaID, err = c.ObjectIDContext(ctx, slaName, ObjectTypeSLA, httpTimeout)
 			if err != nil {
-				return "", err
+				return nil, err
 			}
 		}
 
 		config := map[string]string{}
 		config["slaId"] = slaID.(map[string]interface{})["effectiveSlaDomainId"].(string)
 
-		apiRequest, err := c.Post("v1", fmt.Sprintf("/vmware/vm/%s/snapshot", vmID), config, httpTimeout)
+		apiRequest, err := c.PostContext(ctx, "v1", fmt.Sprintf("/vmware/vm/%s/snapshot", vmID), config, httpTimeout)
 		if err != nil {
-			return "", err
+			return nil, err
 		}
 
-		return apiRequest.(map[string]interface{})["links"].([]interface{})[0].(map[string]interface{})["href"].(string), nil
+		return c.newJob(apiRequest, httpTimeout)
 
 	}
 
-	return "", nil
+	return nil, nil
 }
 
 // OnDemandSnapshotPhysical initiates an on-demand snapshot for a physical host ("hostname"). To use the currently  assigned SLA Domain for the
@@ -584,8 +965,16 @@ func (c *Credentials) OnDemandSnapshotVM(objectName, objectType, slaName string,
 //
 // The function will return:
 //
-//	The job status URL for the on-demand Snapshot
-func (c *Credentials) OnDemandSnapshotPhysical(hostName, slaName, fileset, hostOS string, timeout ...int) (string, error) {
+//	A Job that monitors the on-demand Snapshot. Use Wait() to wait for the Snapshot to complete
+func (c *Credentials) OnDemandSnapshotPhysical(hostName, slaName, fileset, hostOS string, timeout ...int) (*Job, error) {
+	return c.OnDemandSnapshotPhysicalContext(context.Background(), hostName, slaName, fileset, hostOS, timeout...)
+}
+
+// OnDemandSnapshotPhysicalContext is the same as OnDemandSnapshotPhysical with the addition of a context.Context that is used to cancel in-flight requests.
+func (c *Credentials) OnDemandSnapshotPhysicalContext(ctx context.Context, hostName, slaName, fileset, hostOS string, timeout ...int) (*Job, error) {
+
+	ctx, span := c.startSpan(ctx, "OnDemandSnapshotPhysical")
+	defer span.End()
 
 	httpTimeout := httpTimeout(timeout)
 
@@ -600,38 +989,39 @@ func (c *Credentials) OnDemandSnapshotPhysical(hostName, slaName, fileset, hostO
 	}
 
 	if validHostOs[hostOS] == false {
-		return "", fmt.Errorf("The 'hostOS' must be 'Linux' or 'Windows")
+		return nil, fmt.Errorf("The 'hostOS' must be 'Linux' or 'Windows")
 	}
 
-	hostID, err := c.ObjectID(hostName, "physicalHost", httpTimeout)
+	hostID, err := c.ObjectIDContext(ctx, hostName, ObjectTypePhysicalHost, httpTimeout)
 	if err != nil {
-		return "", err
+		return nil, err
 	}
 
-	filesetTemplateID, err := c.ObjectID(fileset, "filesetTemplate", httpTimeout, hostOS)
+	filesetTemplateID, err := c.ObjectIDContext(ctx, fileset, ObjectTypeFilesetTemplate, httpTimeout, hostOS)
 	if err != nil {
-		return "", err
+		return nil, err
 	}
 
-	filesetSummary, err := c.Get("v1", fmt.Sprintf("/fileset?primary_cluster_id=local&host_id=%s&is_relic=false&template_id=%s", hostID, filesetTemplateID))
+	filesetSummary, err := c.getAllContext(ctx, "v1", fmt.Sprintf("/fileset?primary_cluster_id=local&host_id=%s&is_relic=false&template_id=%s", hostID, filesetTemplateID), httpTimeout)
 	if err != nil {
-		return "", err
+		return nil, err
 	}
 
-	if filesetSummary.(map[string]interface{})["total"] == 0 {
-		return "", fmt.Errorf("The Physical Host '%s' is not assigned to the '%s' Fileset", hostName, fileset)
+	filesets, _ := filesetSummary.(map[string]interface{})["data"].([]interface{})
+	if len(filesets) == 0 {
+		return nil, newError(ErrNotFound, "The Physical Host '%s' is not assigned to the '%s' Fileset", hostName, fileset)
 	}
 
-	filesetID := filesetSummary.(map[string]interface{})["data"].([]interface{})[0].(map[string]interface{})["id"].(string)
+	filesetID := filesets[0].(map[string]interface{})["id"].(string)
 
 	var slaID string
 	switch slaName {
 	case "current":
-		slaID = filesetSummary.(map[string]interface{})["data"].([]interface{})[0].(map[string]interface{})["effectiveSlaDomainId"].(string)
+		slaID = filesets[0].(map[string]interface{})["effectiveSlaDomainId"].(string)
 	default:
-		slaID, err = c.ObjectID(slaName, "sla", httpTimeout)
+		slaID, err = c.ObjectIDContext(ctx, slaName, ObjectTypeSLA, httpTimeout)
 		if err != nil {
-			return "", err
+			return nil, err
 		}
 
 	}
@@ -639,43 +1029,39 @@ func (c *Credentials) OnDemandSnapshotPhysical(hostName, slaName, fileset, hostO
 	config := map[string]string{}
 	config["slaId"] = slaID
 
-	apiRequest, err := c.Post("v1", fmt.Sprintf("/fileset/%s/snapshot", filesetID), config, httpTimeout)
+	apiRequest, err := c.PostContext(ctx, "v1", fmt.Sprintf("/fileset/%s/snapshot", filesetID), config, httpTimeout)
 	if err != nil {
-		return "", err
+		return nil, err
 	}
 
-	return apiRequest.(map[string]interface{})["links"].([]interface{})[0].(map[string]interface{})["href"].(string), nil
+	return c.newJob(apiRequest, httpTimeout)
 }
 
+// DateTimeConversion converts "dateTime", interpreted in the time zone of the Rubrik cluster, to UTC formatted as RFC3339
+// (ex. 2019-04-10T00:56:00Z). Every format accepted by ParseDateTime() is supported, including the original
+// "Month-Day-Year Hour:Minute AM/PM" format (ex. 04-09-2019 05:56 PM).
 func (c *Credentials) DateTimeConversion(dateTime string, timeout ...int) (string, error) {
+	return c.DateTimeConversionContext(context.Background(), dateTime, timeout...)
+}
 
-	httpTimeout := httpTimeout(timeout)
-
-	apiRequest, err := c.Get("v1", "/cluster/me", httpTimeout)
-	if err != nil {
-		return "", err
-	}
-
-	// Convert the API Response (map[string]interface{}) to a struct
-	var cluster Cluster
-	mapErr := mapstructure.Decode(apiRequest, &cluster)
-	if mapErr != nil {
-		return "", mapErr
-	}
+// DateTimeConversionContext is the same as DateTimeConversion with the addition of a context.Context that is used to cancel in-flight requests.
+func (c *Credentials) DateTimeConversionContext(ctx context.Context, dateTime string, timeout ...int) (string, error) {
 
-	currentTimezone, _ := time.LoadLocation(cluster.Timezone.Timezone)
-	// Month-Day-Year
+	ctx, span := c.startSpan(ctx, "DateTimeConversion")
+	defer span.End()
 
-	snapshotDateTime, err := time.ParseInLocation("01-02-2006 3:04 PM", dateTime, currentTimezone)
+	snapshotDateTime, err := c.ClusterDateTimeContext(ctx, dateTime, timeout...)
 	if err != nil {
-		return "", fmt.Errorf("The provided 'dateTime' does not match the required format (Month-Day-Year Hour:Minute AM/PM). Ex. 01-02-2006 3:04 PM")
+		return "", err
 	}
 
 	return snapshotDateTime.UTC().Format(time.RFC3339), nil
 
 }
 
-// RecoverFileDownload initiates to create a file download job from a fileset backup.
+// RecoverFileDownload initiates to create a file download job from a fileset backup. The "dateTime" is interpreted in the
+// time zone of the Rubrik cluster and can use any format accepted by ParseDateTime() (ex. 04-09-2019 05:56 PM). The
+// snapshot taken during that minute is used.
 //
 // Valid "hostOS" choices are:
 //
@@ -683,9 +1069,40 @@ func (c *Credentials) DateTimeConversion(dateTime string, timeout ...int) (strin
 //
 // The function will return:
 //
-//	The job status URL for file download job from a fileset backup
-func (c *Credentials) RecoverFileDownload(hostName, fileset, hostOS, filePath, dateTime string, timeout ...int) (string, error) {
-	httpTimeout := httpTimeout(timeout)
+//	A Job that monitors the file download job from a fileset backup. Use Wait() to wait for the download link to be ready
+func (c *Credentials) RecoverFileDownload(hostName, fileset, hostOS, filePath, dateTime string, timeout ...int) (*Job, error) {
+	return c.RecoverFileDownloadContext(context.Background(), hostName, fileset, hostOS, filePath, dateTime, timeout...)
+}
+
+// RecoverFileDownloadContext is the same as RecoverFileDownload with the addition of a context.Context that is used to cancel in-flight requests.
+func (c *Credentials) RecoverFileDownloadContext(ctx context.Context, hostName, fileset, hostOS, filePath, dateTime string, timeout ...int) (*Job, error) {
+	ctx, span := c.startSpan(ctx, "RecoverFileDownload")
+	defer span.End()
+
+	snapshotDateTime, err := c.ClusterDateTimeContext(ctx, dateTime, timeout...)
+	if err != nil {
+		return nil, err
+	}
+
+	return c.recoverFileDownload(ctx, hostName, fileset, hostOS, filePath, snapshotDateTime, dateTime, httpTimeout(timeout))
+}
+
+// RecoverFileDownloadAt is the same as RecoverFileDownload with the point in time of the snapshot provided as a time.Time.
+func (c *Credentials) RecoverFileDownloadAt(hostName, fileset, hostOS, filePath string, dateTime time.Time, timeout ...int) (*Job, error) {
+	return c.RecoverFileDownloadAtContext(context.Background(), hostName, fileset, hostOS, filePath, dateTime, timeout...)
+}
+
+// RecoverFileDownloadAtContext is the same as RecoverFileDownloadAt with the addition of a context.Context that is used to cancel in-flight requests.
+func (c *Credentials) RecoverFileDownloadAtContext(ctx context.Context, hostName, fileset, hostOS, filePath string, dateTime time.Time, timeout ...int) (*Job, error) {
+	ctx, span := c.startSpan(ctx, "RecoverFileDownloadAt")
+	defer span.End()
+
+	return c.recoverFileDownload(ctx, hostName, fileset, hostOS, filePath, dateTime, dateTime.Format(time.RFC3339), httpTimeout(timeout))
+}
+
+// recoverFileDownload creates the file download job from the fileset snapshot taken during the minute of "dateTime".
+// The "label" is the dateTime as provided by the caller for error messages.
+func (c *Credentials) recoverFileDownload(ctx context.Context, hostName, fileset, hostOS, filePath string, dateTime time.Time, label string, httpTimeout int) (*Job, error) {
 
 	validHostOs := map[string]bool{
 		"Linux":   true,
@@ -693,73 +1110,55 @@ func (c *Credentials) RecoverFileDownload(hostName, fileset, hostOS, filePath, d
 	}
 
 	if validHostOs[hostOS] == false {
-		return "", fmt.Errorf("The 'hostOS' must be 'Linux' or 'Windows")
+		return nil, fmt.Errorf("The 'hostOS' must be 'Linux' or 'Windows")
 	}
 
-	hostID, err := c.ObjectID(hostName, "physicalHost", httpTimeout)
+	hostID, err := c.ObjectIDContext(ctx, hostName, ObjectTypePhysicalHost, httpTimeout)
 	if err != nil {
-		return "", err
+		return nil, err
 	}
 
-	filesetTemplateID, err := c.ObjectID(fileset, "filesetTemplate", httpTimeout, hostOS)
+	filesetTemplateID, err := c.ObjectIDContext(ctx, fileset, ObjectTypeFilesetTemplate, httpTimeout, hostOS)
 	if err != nil {
-		return "", err
+		return nil, err
 	}
 
-	filesetSummary, err := c.Get("v1", fmt.Sprintf("/fileset?primary_cluster_id=local&host_id=%s&is_relic=false&template_id=%s", hostID, filesetTemplateID))
+	filesetSummary, err := c.getAllContext(ctx, "v1", fmt.Sprintf("/fileset?primary_cluster_id=local&host_id=%s&is_relic=false&template_id=%s", hostID, filesetTemplateID), httpTimeout)
 	if err != nil {
-		return "", err
+		return nil, err
 	}
 
-	if filesetSummary.(map[string]interface{})["total"] == 0 {
-		return "", fmt.Errorf("The Physical Host '%s' is not assigned to the '%s' Fileset", hostName, fileset)
+	filesets, _ := filesetSummary.(map[string]interface{})["data"].([]interface{})
+	if len(filesets) == 0 {
+		return nil, newError(ErrNotFound, "The Physical Host '%s' is not assigned to the '%s' Fileset", hostName, fileset)
 	}
 
-	filesetID := filesetSummary.(map[string]interface{})["data"].([]interface{})[0].(map[string]interface{})["id"].(string)
+	filesetID := filesets[0].(map[string]interface{})["id"].(string)
 
-	filesetDetail, err := c.Get("v1", fmt.Sprintf("/fileset/%s", filesetID))
+	snapshots, err := c.SnapshotsContext(ctx, ObjectTypeFileset, filesetID, httpTimeout)
 	if err != nil {
-		return "", err
-	}
-	snapshotSummary := filesetDetail.(map[string]interface{})["snapshots"].([]interface{})
-
-	if len(snapshotSummary) == 0 {
-		return "", fmt.Errorf("The Physical Host '%s' does not have any snapshot by '%s' Fileset", hostName, fileset)
+		return nil, err
 	}
 
-	snapshotDateTimeStr, err := c.DateTimeConversion(dateTime)
-	if err != nil {
-		return "", err
+	if len(snapshots) == 0 {
+		return nil, newError(ErrNotFound, "The Physical Host '%s' does not have any snapshot by '%s' Fileset", hostName, fileset)
 	}
 
-	snapshotDateTime, _ := time.Parse(time.RFC3339, snapshotDateTimeStr)
-	if err != nil {
-		return "", err
+	minute := dateTime.Truncate(time.Minute)
+	snapshot, found := OnOrAfter(minute).Where(TakenBetween(minute, minute.Add(time.Minute))).Select(snapshots)
+	if found == false {
+		return nil, newError(ErrNotFound, "The Physical Host '%s' does not have any snapshot at '%s'", hostName, label)
 	}
+	snapshotID := snapshot.ID
 
-	var snapshotID string
-	for _, v := range snapshotSummary {
-		date, _ := time.Parse(time.RFC3339, v.(map[string]interface{})["date"].(string))
-		if err != nil {
-			return "", err
-		}
-		diff := date.Sub(snapshotDateTime)
-		if 0 <= diff && diff < time.Duration(60)*time.Second {
-			snapshotID = v.(map[string]interface{})["id"].(string)
-			break
-		}
-	}
-	if snapshotID == "" {
-		return "", fmt.Errorf("The Physical Host '%s' does not have any snapshot at '%s'", hostName, dateTime)
-	}
 	config := map[string]string{
 		"sourceDir": filePath,
 	}
-	apiRequest, err := c.Post("v1", fmt.Sprintf("/fileset/snapshot/%s/download_file", snapshotID), config)
+	apiRequest, err := c.PostContext(ctx, "v1", fmt.Sprintf("/fileset/snapshot/%s/download_file", snapshotID), config)
 
 	if err != nil {
-		return "", err
+		return nil, err
 	}
 
-	return apiRequest.(map[string]interface{})["links"].([]interface{})[0].(map[string]interface{})["href"].(string), nil
+	return c.newJob(apiRequest, httpTimeout)
 }
@@ -0,0 +1,195 @@
+// Copyright 2018 Rubrik, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License prop
+//  http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rubrikcdm
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// legacyDateTimeLayout is the "Month-Day-Year Hour:Minute AM/PM" layout originally accepted by DateTimeConversion().
+const legacyDateTimeLayout = "01-02-2006 3:04 PM"
+
+// localDateTimeLayouts are the layouts, without a time zone, that ParseDateTime() interprets in the provided location.
+var localDateTimeLayouts = []string{
+	"2006-01-02T15:04:05.999999999",
+	"2006-01-02T15:04",
+	"2006-01-02 15:04:05",
+	"2006-01-02 15:04",
+	"2006-01-02",
+	legacyDateTimeLayout,
+	"01-02-2006 3:04PM",
+}
+
+// timeOfDayLayouts are the layouts accepted after "today" and "yesterday" (ex. yesterday 23:00).
+var timeOfDayLayouts = []string{"15:04", "15:04:05", "3:04 PM", "3:04PM", "3 PM", "3PM"}
+
+// relativeDateTimePattern matches an amount of time in the past, ex. 2h ago or 3 days ago.
+var relativeDateTimePattern = regexp.MustCompile(`^(\d+)\s*([a-z]+)\s+ago$`)
+
+// ParseDateTime returns the point in time described by "dateTime". Values without a time zone are interpreted in the
+// provided location, use ClusterDateTime() to interpret them in the time zone of the Rubrik cluster. The supported
+// formats are:
+//
+//	RFC3339, ex. 2019-04-09T17:56:00Z or 2019-04-09T10:56:00-07:00
+//	ISO 8601 without a time zone, ex. 2019-04-09T17:56:00, 2019-04-09 17:56 or 2019-04-09
+//	Month-Day-Year Hour:Minute AM/PM, ex. 04-09-2019 05:56 PM
+//	now, today and yesterday, optionally followed by a time of day, ex. yesterday 23:00 or today 5:30 PM
+//	an amount of seconds, minutes, hours, days or weeks ago, ex. 2h ago, 90 minutes ago or 3 days ago
+func ParseDateTime(dateTime string, location *time.Location) (time.Time, error) {
+	return parseDateTime(dateTime, location, time.Now())
+}
+
+// parseDateTime is the same as ParseDateTime with relative expressions resolved from "now".
+func parseDateTime(dateTime string, location *time.Location, now time.Time) (time.Time, error) {
+
+	if location == nil {
+		location = time.UTC
+	}
+	now = now.In(location)
+	value := strings.TrimSpace(dateTime)
+
+	if parsed, err := time.Parse(time.RFC3339Nano, value); err == nil {
+		return parsed, nil
+	}
+	for _, layout := range localDateTimeLayouts {
+		if parsed, err := time.ParseInLocation(layout, value, location); err == nil {
+			return parsed, nil
+		}
+	}
+
+	lower := strings.Join(strings.Fields(strings.ToLower(value)), " ")
+	if lower == "now" {
+		return now, nil
+	}
+
+	if match := relativeDateTimePattern.FindStringSubmatch(lower); match != nil {
+		amount, err := strconv.Atoi(match[1])
+		if err == nil {
+			switch match[2] {
+			case "s", "sec", "secs", "second", "seconds":
+				return now.Add(-time.Duration(amount) * time.Second), nil
+			case "m", "min", "mins", "minute", "minutes":
+				return now.Add(-time.Duration(amount) * time.Minute), nil
+			case "h", "hr", "hrs", "hour", "hours":
+				return now.Add(-time.Duration(amount) * time.Hour), nil
+			case "d", "day", "days":
+				return now.AddDate(0, 0, -amount), nil
+			case "w", "week", "weeks":
+				return now.AddDate(0, 0, -7*amount), nil
+			}
+		}
+	}
+	if strings.HasSuffix(lower, " ago") {
+		if duration, err := time.ParseDuration(strings.TrimSuffix(lower, " ago")); err == nil {
+			return now.Add(-duration), nil
+		}
+	}
+
+	for daysAgo, day := range []string{"today", "yesterday"} {
+		if lower != day && strings.HasPrefix(lower, day+" ") == false {
+			continue
+		}
+		date := now.AddDate(0, 0, -daysAgo)
+		midnight := time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, location)
+
+		timeOfDay := strings.TrimSpace(strings.TrimPrefix(lower, day))
+		if timeOfDay == "" {
+			return midnight, nil
+		}
+		for _, layout := range timeOfDayLayouts {
+			if clock, err := time.Parse(layout, strings.ToUpper(timeOfDay)); err == nil {
+				return time.Date(midnight.Year(), midnight.Month(), midnight.Day(), clock.Hour(), clock.Minute(), clock.Second(), 0, location), nil
+			}
+		}
+	}
+
+	return time.Time{}, fmt.Errorf("The provided 'dateTime' does not match a supported format. Ex. 2019-04-09T17:56:00Z, 2019-04-09 17:56, 04-09-2019 5:56 PM, 2h ago or yesterday 23:00")
+}
+
+// timezoneCache holds the time zone of the Rubrik cluster once it has been requested.
+type timezoneCache struct {
+	mu       sync.Mutex
+	location *time.Location
+}
+
+// ClusterTimezone returns the time zone configured on the Rubrik cluster. The Credentials created by Connect(),
+// ConnectAPIToken(), ConnectEnv() or NewClient() request the time zone once and reuse it until ConfigureTimezone()
+// changes it.
+func (c *Credentials) ClusterTimezone(timeout ...int) (*time.Location, error) {
+	return c.ClusterTimezoneContext(context.Background(), timeout...)
+}
+
+// ClusterTimezoneContext is the same as ClusterTimezone with the addition of a context.Context that is used to cancel in-flight requests.
+func (c *Credentials) ClusterTimezoneContext(ctx context.Context, timeout ...int) (*time.Location, error) {
+
+	if c.timezone != nil {
+		c.timezone.mu.Lock()
+		defer c.timezone.mu.Unlock()
+
+		if c.timezone.location != nil {
+			return c.timezone.location, nil
+		}
+	}
+
+	cluster, err := GetIntoContext[Cluster](ctx, c, "v1", "/cluster/me", timeout...)
+	if err != nil {
+		return nil, err
+	}
+
+	location, err := time.LoadLocation(cluster.Timezone.Timezone)
+	if err != nil {
+		return nil, fmt.Errorf("Unable to load the '%s' timezone of the Rubrik cluster: %w", cluster.Timezone.Timezone, err)
+	}
+
+	if c.timezone != nil {
+		c.timezone.location = location
+	}
+
+	return location, nil
+}
+
+// ClusterDateTime returns the point in time described by "dateTime" using ParseDateTime() with the time zone of the Rubrik
+// cluster, ex. 04-09-2019 05:56 PM is 5:56 PM in the time zone of the Rubrik cluster.
+func (c *Credentials) ClusterDateTime(dateTime string, timeout ...int) (time.Time, error) {
+	return c.ClusterDateTimeContext(context.Background(), dateTime, timeout...)
+}
+
+// ClusterDateTimeContext is the same as ClusterDateTime with the addition of a context.Context that is used to cancel in-flight requests.
+func (c *Credentials) ClusterDateTimeContext(ctx context.Context, dateTime string, timeout ...int) (time.Time, error) {
+
+	ctx, span := c.startSpan(ctx, "ClusterDateTime")
+	defer span.End()
+
+	location, err := c.ClusterTimezoneContext(ctx, timeout...)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	return ParseDateTime(dateTime, location)
+}
+
+// forgetClusterTimezone removes the cached time zone of the Rubrik cluster.
+func (c *Credentials) forgetClusterTimezone() {
+	if c.timezone != nil {
+		c.timezone.mu.Lock()
+		c.timezone.location = nil
+		c.timezone.mu.Unlock()
+	}
+}
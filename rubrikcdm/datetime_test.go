@@ -0,0 +1,94 @@
+// Copyright 2018 Rubrik, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License prop
+//  http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rubrikcdm
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseDateTime(t *testing.T) {
+
+	location, err := time.LoadLocation("America/Los_Angeles")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// 2019-04-09 17:56:30 in Los Angeles
+	now := time.Date(2019, 4, 10, 0, 56, 30, 0, time.UTC)
+
+	tests := []struct {
+		dateTime string
+		want     time.Time
+	}{
+		// RFC3339 values keep their own time zone
+		{"2019-04-10T00:56:00Z", time.Date(2019, 4, 10, 0, 56, 0, 0, time.UTC)},
+		{"2019-04-09T10:56:00-07:00", time.Date(2019, 4, 9, 17, 56, 0, 0, time.UTC)},
+		// Values without a time zone are interpreted in the location
+		{"2019-04-09T17:56:00", time.Date(2019, 4, 9, 17, 56, 0, 0, location)},
+		{"2019-04-09T17:56", time.Date(2019, 4, 9, 17, 56, 0, 0, location)},
+		{"2019-04-09 17:56:10", time.Date(2019, 4, 9, 17, 56, 10, 0, location)},
+		{"2019-04-09 17:56", time.Date(2019, 4, 9, 17, 56, 0, 0, location)},
+		{"2019-04-09", time.Date(2019, 4, 9, 0, 0, 0, 0, location)},
+		// Legacy DateTimeConversion() layout
+		{"04-09-2019 05:56 PM", time.Date(2019, 4, 9, 17, 56, 0, 0, location)},
+		{"04-09-2019 5:56 AM", time.Date(2019, 4, 9, 5, 56, 0, 0, location)},
+		{"04-09-2019 5:56PM", time.Date(2019, 4, 9, 17, 56, 0, 0, location)},
+		// Relative expressions
+		{"now", now},
+		{" NOW ", now},
+		{"30s ago", now.Add(-30 * time.Second)},
+		{"90 minutes ago", now.Add(-90 * time.Minute)},
+		{"2h ago", now.Add(-2 * time.Hour)},
+		{"1 hour ago", now.Add(-time.Hour)},
+		{"3 days ago", time.Date(2019, 4, 6, 17, 56, 30, 0, location)},
+		{"2 weeks ago", time.Date(2019, 3, 26, 17, 56, 30, 0, location)},
+		{"1h30m ago", now.Add(-90 * time.Minute)},
+		{"today", time.Date(2019, 4, 9, 0, 0, 0, 0, location)},
+		{"today 5:30 PM", time.Date(2019, 4, 9, 17, 30, 0, 0, location)},
+		{"yesterday", time.Date(2019, 4, 8, 0, 0, 0, 0, location)},
+		{"yesterday 23:00", time.Date(2019, 4, 8, 23, 0, 0, 0, location)},
+		{"Yesterday 11PM", time.Date(2019, 4, 8, 23, 0, 0, 0, location)},
+	}
+
+	for _, test := range tests {
+		got, err := parseDateTime(test.dateTime, location, now)
+		if err != nil {
+			t.Errorf("parseDateTime(%q) returned an error: %v", test.dateTime, err)
+			continue
+		}
+		if got.Equal(test.want) == false {
+			t.Errorf("parseDateTime(%q) = %s, want %s", test.dateTime, got, test.want)
+		}
+	}
+}
+
+func TestParseDateTimeInvalid(t *testing.T) {
+
+	for _, dateTime := range []string{"", "tomorrow", "2019-13-01", "04-09-2019", "yesterday 25:00", "3 fortnights ago", "ago", "2h"} {
+		if got, err := parseDateTime(dateTime, time.UTC, time.Now()); err == nil {
+			t.Errorf("parseDateTime(%q) = %s, want an error", dateTime, got)
+		}
+	}
+}
+
+func TestParseDateTimeDefaultLocation(t *testing.T) {
+
+	got, err := ParseDateTime("2019-04-09 17:56", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := time.Date(2019, 4, 9, 17, 56, 0, 0, time.UTC); got.Equal(want) == false {
+		t.Errorf("ParseDateTime() with a nil location = %s, want %s", got, want)
+	}
+}
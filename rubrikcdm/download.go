@@ -0,0 +1,331 @@
+// Copyright 2018 Rubrik, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License prop
+//  http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rubrikcdm
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DownloadProgressFunc is called every time a chunk of a download has been written. "total" is the size of the file, or
+// -1 when the Rubrik cluster did not report it.
+type DownloadProgressFunc func(written, total int64)
+
+type downloadProgressKey struct{}
+
+// WithDownloadProgress returns a copy of the context that calls "fn" with the number of bytes of the file downloaded so far,
+// including the "offset", by DownloadRecoveredFilesContext(), ex:
+//
+//	ctx = rubrikcdm.WithDownloadProgress(ctx, func(written, total int64) {
+//		fmt.Printf("%d/%d bytes\n", written, total)
+//	})
+func WithDownloadProgress(ctx context.Context, fn DownloadProgressFunc) context.Context {
+	return context.WithValue(ctx, downloadProgressKey{}, fn)
+}
+
+// contentRangePattern matches the Content-Range header of a 206 Partial Content response, ex. bytes 100-199/200.
+var contentRangePattern = regexp.MustCompile(`^bytes (\d+)-\d+/(\d+|\*)$`)
+
+// unsatisfiedRangePattern matches the Content-Range header of a 416 Range Not Satisfiable response, ex. bytes */200.
+var unsatisfiedRangePattern = regexp.MustCompile(`^bytes \*/(\d+)$`)
+
+// DownloadRecoveredFiles waits for the job returned by RecoverFileDownload() to succeed and streams the file it prepared
+// to "w" through the transport and authentication of the Client, without buffering it in memory. A download interrupted
+// by a network error is resumed with an HTTP Range request according to the RetryPolicy of the Client. The "timeout"
+// applies to each request and to every read of the download.
+//
+// Use "offset" to resume a previous download that stopped after "offset" bytes (ex. the size of a partially written
+// file opened in append mode), or 0 to download the whole file. An "offset" past the end of the file returns an error.
+//
+// The download link must point to a node of the Rubrik cluster, which is either the node of the Credentials, one of the
+// nodes provided through WithNodes() or WithNodeDiscovery(), or one of the nodes returned by ClusterNodeIP(), so that
+// credentials are never sent to another host. A Client created with
+// WithCassette() can not download files because the cassette buffers every response in memory.
+//
+// The function will return:
+//
+//	The number of bytes written to "w", which is also returned along with an error when the download failed.
+func (c *Credentials) DownloadRecoveredFiles(job *Job, w io.Writer, offset int64, timeout ...int) (int64, error) {
+	return c.DownloadRecoveredFilesContext(context.Background(), job, w, offset, timeout...)
+}
+
+// DownloadRecoveredFilesContext is the same as DownloadRecoveredFiles with the addition of a context.Context that is used to cancel in-flight requests and any polling.
+func (c *Credentials) DownloadRecoveredFilesContext(ctx context.Context, job *Job, w io.Writer, offset int64, timeout ...int) (int64, error) {
+
+	ctx, span := c.startSpan(ctx, "DownloadRecoveredFiles")
+	defer span.End()
+
+	httpTimeout := httpTimeout(timeout)
+
+	if offset < 0 {
+		return 0, errors.New("The 'offset' must be greater than or equal to 0")
+	}
+
+	if c.config != nil && c.config.cassette != nil {
+		return 0, errors.New("DownloadRecoveredFiles() can not be used by a Client created with WithCassette()")
+	}
+
+	status, err := job.WaitContext(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	var downloadURL string
+	for _, link := range status.Links {
+		if link.Rel == "result" {
+			downloadURL = link.Href
+		}
+	}
+	if downloadURL == "" {
+		return 0, fmt.Errorf("The job %s did not return a download link", status.ID)
+	}
+	if strings.HasPrefix(downloadURL, "/") {
+		downloadURL = fmt.Sprintf("https://%s%s", c.NodeIP, downloadURL)
+	}
+	parsedURL, err := url.Parse(downloadURL)
+	if err != nil {
+		return 0, fmt.Errorf("The job %s returned an invalid download link: %w", status.ID, err)
+	}
+	clusterNode, err := c.clusterNode(ctx, parsedURL.Host, httpTimeout)
+	if err != nil {
+		return 0, err
+	}
+	if clusterNode == false {
+		return 0, fmt.Errorf("The download link %s does not point to a node of the Rubrik cluster", downloadURL)
+	}
+
+	progress, _ := ctx.Value(downloadProgressKey{}).(DownloadProgressFunc)
+	retryPolicy := c.retryPolicy()
+	reauthenticated := false
+	position := offset
+
+	for attempt := 1; ; attempt++ {
+
+		written, err := c.downloadRange(ctx, downloadURL, w, position, progress, httpTimeout)
+		position += written
+		if err == nil || errors.Is(err, errDownloadWrite) || errors.Is(err, errDownloadRange) {
+			return position - offset, err
+		}
+		if ctx.Err() != nil {
+			// The caller cancelled the download or its deadline passed
+			return position - offset, ctx.Err()
+		}
+
+		var apiErr *APIError
+		if errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusUnauthorized && reauthenticated == false && c.usesSession() {
+			// The session token expired, create a new session without counting it as an attempt
+			c.config.clearSession()
+			reauthenticated = true
+			attempt--
+			continue
+		}
+
+		if written > 0 {
+			// The download progressed, only consecutive failures count towards the retry limit
+			attempt = 1
+		}
+		wait, retry := retryPolicy.retryDownload(attempt, err)
+		if retry == false {
+			if apiErr != nil {
+				return position - offset, err
+			}
+			return position - offset, fmt.Errorf("The download was interrupted after %d bytes: %w", position, err)
+		}
+		if err := sleepContext(ctx, wait); err != nil {
+			return position - offset, err
+		}
+	}
+}
+
+// clusterNode returns true when "host" is the node of the Credentials, one of the nodes of the Client or, as the download
+// link can be served by any node, one of the nodes returned by ClusterNodeIP(). A host without a port matches the same
+// host on port 443.
+func (c *Credentials) clusterNode(ctx context.Context, host string, timeout int) (bool, error) {
+
+	nodes := []string{c.NodeIP}
+	if c.config != nil && c.config.nodes != nil {
+		c.config.nodes.mu.Lock()
+		for _, n := range c.config.nodes.nodes {
+			nodes = append(nodes, n.address)
+		}
+		c.config.nodes.mu.Unlock()
+	}
+	if containsNode(nodes, host) {
+		return true, nil
+	}
+
+	nodeIPs, err := c.ClusterNodeIPContext(ctx, timeout)
+	if err != nil {
+		return false, err
+	}
+
+	return containsNode(nodeIPs, host), nil
+}
+
+// containsNode returns true when "host" is one of the nodes.
+func containsNode(nodes []string, host string) bool {
+	for _, node := range nodes {
+		if strings.EqualFold(withHTTPSPort(node), withHTTPSPort(host)) {
+			return true
+		}
+	}
+	return false
+}
+
+// withHTTPSPort appends the default HTTPS port to a host without a port.
+func withHTTPSPort(host string) string {
+	if _, _, err := net.SplitHostPort(host); err == nil {
+		return host
+	}
+	return net.JoinHostPort(strings.Trim(host, "[]"), "443")
+}
+
+var (
+	// errDownloadWrite wraps the error returned by the io.Writer of a download, which is not retried.
+	errDownloadWrite = errors.New("Unable to write the download")
+	// errDownloadRange wraps the errors caused by a range the Rubrik cluster can not serve, which are not retried.
+	errDownloadRange = errors.New("The Rubrik cluster can not serve the requested range of the download")
+	// errDownloadStalled is returned when a download did not receive any data for the duration of the timeout.
+	errDownloadStalled = errors.New("The download did not receive any data before the timeout")
+)
+
+// downloadRange requests the download from the "start" byte, copies it to "w" and returns the number of bytes written.
+func (c *Credentials) downloadRange(ctx context.Context, downloadURL string, w io.Writer, start int64, progress DownloadProgressFunc, timeout int) (int64, error) {
+
+	// The timeout applies to every read instead of the whole download
+	parent := ctx
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	idle := time.AfterFunc(time.Duration(timeout)*time.Second, cancel)
+	defer idle.Stop()
+
+	written, err := c.copyRange(ctx, downloadURL, w, start, progress, idle, timeout)
+	if err != nil && parent.Err() == nil && ctx.Err() != nil {
+		return written, errDownloadStalled
+	}
+	return written, err
+}
+
+// copyRange sends the request for the download from the "start" byte and copies the response to "w". The "idle" timer is
+// reset every time data is received.
+func (c *Credentials) copyRange(ctx context.Context, downloadURL string, w io.Writer, start int64, progress DownloadProgressFunc, idle *time.Timer, timeout int) (int64, error) {
+
+	request, err := http.NewRequestWithContext(ctx, "GET", downloadURL, nil)
+	if err != nil {
+		return 0, err
+	}
+	request.Header.Set("User-Agent", userAgent)
+	if start > 0 {
+		request.Header.Set("Range", fmt.Sprintf("bytes=%d-", start))
+	}
+	if err := c.authorize(ctx, request, timeout); err != nil {
+		return 0, err
+	}
+
+	client := c.httpClient(timeout)
+	client.Timeout = 0
+
+	begin := time.Now()
+	response, err := client.Do(request)
+	c.logRequest(ctx, request, nil, response, nil, 1, time.Since(begin), err)
+	if err != nil {
+		return 0, err
+	}
+	defer response.Body.Close()
+
+	// Skip the bytes that have already been written when the Rubrik cluster ignores the Range header
+	skip := int64(0)
+	total := int64(-1)
+	switch response.StatusCode {
+	case http.StatusOK:
+		total = response.ContentLength
+		skip = start
+	case http.StatusPartialContent:
+		match := contentRangePattern.FindStringSubmatch(response.Header.Get("Content-Range"))
+		if match == nil || match[1] != strconv.FormatInt(start, 10) {
+			return 0, fmt.Errorf("%w, it responded with the unexpected Content-Range '%s'", errDownloadRange, response.Header.Get("Content-Range"))
+		}
+		if size, err := strconv.ParseInt(match[2], 10, 64); err == nil {
+			total = size
+		}
+	case http.StatusRequestedRangeNotSatisfiable:
+		match := unsatisfiedRangePattern.FindStringSubmatch(response.Header.Get("Content-Range"))
+		if match == nil {
+			return 0, fmt.Errorf("%w, it rejected the download starting at byte %d", errDownloadRange, start)
+		}
+		size, _ := strconv.ParseInt(match[1], 10, 64)
+		if start != size {
+			return 0, fmt.Errorf("%w, the download starts at byte %d but the file is %d bytes", errDownloadRange, start, size)
+		}
+		// The whole file has already been downloaded
+		return 0, nil
+	default:
+		body, _ := ioutil.ReadAll(response.Body)
+		if _, err := parseAPIResponse(response, body); err != nil {
+			return 0, err
+		}
+		return 0, &APIError{StatusCode: response.StatusCode, Method: "GET", Endpoint: response.Request.URL.RequestURI()}
+	}
+
+	written := int64(0)
+	buffer := make([]byte, 32*1024)
+	for {
+		n, readErr := response.Body.Read(buffer)
+		idle.Reset(time.Duration(timeout) * time.Second)
+
+		chunk := buffer[:n]
+		if skip > 0 {
+			skipped := skip
+			if skipped > int64(len(chunk)) {
+				skipped = int64(len(chunk))
+			}
+			chunk = chunk[skipped:]
+			skip -= skipped
+		}
+
+		if len(chunk) > 0 {
+			m, err := w.Write(chunk)
+			written += int64(m)
+			if err == nil && m < len(chunk) {
+				err = io.ErrShortWrite
+			}
+			if err != nil {
+				return written, fmt.Errorf("%w: %w", errDownloadWrite, err)
+			}
+			if progress != nil {
+				progress(start+written, total)
+			}
+		}
+
+		if readErr == io.EOF {
+			if total >= 0 && start+written < total {
+				return written, io.ErrUnexpectedEOF
+			}
+			return written, nil
+		}
+		if readErr != nil {
+			return written, readErr
+		}
+	}
+}
@@ -0,0 +1,73 @@
+// Copyright 2018 Rubrik, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License prop
+//  http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rubrikcdm_test
+
+import (
+	"bytes"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/rubrikinc/rubrik-sdk-for-go/rubrikcdm"
+	"github.com/rubrikinc/rubrik-sdk-for-go/rubrikcdm/rubrikcdmtest"
+)
+
+func TestDownloadRecoveredFilesFromAnotherNode(t *testing.T) {
+
+	server := rubrikcdmtest.NewServer()
+	defer server.Close()
+	jobStatusURL := server.AddDownload([]byte("127.0.0.1 localhost"))
+
+	// Connect through the "localhost" name of the node, the download link points to its 127.0.0.1 IP address that is
+	// only returned by ClusterNodeIP()
+	httpClient := server.Client()
+	transport := httpClient.Transport.(*http.Transport).Clone()
+	transport.TLSClientConfig.ServerName = "example.com"
+	httpClient.Transport = transport
+
+	credentials := rubrikcdm.Connect(strings.Replace(server.NodeIP(), "127.0.0.1", "localhost", 1), "admin", "password")
+	rubrik, err := rubrikcdm.NewClient(credentials, rubrikcdm.WithHTTPClient(httpClient))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var content bytes.Buffer
+	if _, err := rubrik.DownloadRecoveredFiles(rubrik.NewJob(jobStatusURL), &content, 0); err != nil {
+		t.Fatal(err)
+	}
+	if content.String() != "127.0.0.1 localhost" {
+		t.Errorf("DownloadRecoveredFiles() wrote %q", content.String())
+	}
+}
+
+func TestDownloadRecoveredFilesFromAnotherHost(t *testing.T) {
+
+	server := rubrikcdmtest.NewServer()
+	defer server.Close()
+	server.HandleFunc("GET", "/api/v1/fileset/request/DOWNLOAD_FILE_01", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"id":"DOWNLOAD_FILE_01","status":"SUCCEEDED","links":[{"rel":"result","href":"https://198.51.100.1/download_dir/01.zip"}]}`))
+	})
+
+	rubrik, err := server.NewClient()
+	if err != nil {
+		t.Fatal(err)
+	}
+	job := rubrik.NewJob("https://" + server.NodeIP() + "/api/v1/fileset/request/DOWNLOAD_FILE_01")
+
+	_, err = rubrik.DownloadRecoveredFiles(job, &bytes.Buffer{}, 0)
+	if err == nil || strings.Contains(err.Error(), "does not point to a node of the Rubrik cluster") == false {
+		t.Errorf("DownloadRecoveredFiles() of a link to another host = %v, want an error", err)
+	}
+}
@@ -0,0 +1,86 @@
+// Copyright 2018 Rubrik, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License prop
+//  http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rubrikcdm
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+// Sentinel errors returned by the SDK functions. Use errors.Is() to check for them, ex:
+//
+//	_, err := rubrik.AssignSLA("vm01", "vmware", "Gold")
+//	if errors.Is(err, rubrikcdm.ErrNoChangeRequired) {
+//		// The VM is already assigned to the Gold SLA Domain
+//	}
+var (
+	// ErrNoChangeRequired is returned by the idempotent functions when the Rubrik cluster is already in the requested state.
+	ErrNoChangeRequired = errors.New("No change required")
+	// ErrNotFound is returned when the requested object does not exist on the Rubrik cluster.
+	ErrNotFound = errors.New("The object was not found on the Rubrik cluster")
+	// ErrMultipleMatches is returned when a name matches more than one object on the Rubrik cluster.
+	ErrMultipleMatches = errors.New("Multiple objects matching the provided name were found on the Rubrik cluster")
+)
+
+// APIError is returned when the Rubrik cluster responds to an API call with an error. Use errors.As() to access it, ex:
+//
+//	var apiErr *rubrikcdm.APIError
+//	if errors.As(err, &apiErr) && apiErr.StatusCode == 403 {
+//		// The user does not have the required privileges
+//	}
+type APIError struct {
+	// StatusCode is the HTTP status code returned by the Rubrik cluster
+	StatusCode int
+	// ErrorType is the "errorType" field of the API response (ex. user_error or server_error) when present
+	ErrorType string
+	// Message is the "message" field of the API response when present
+	Message string
+	// Method is the HTTP method of the API call
+	Method string
+	// Endpoint is the path and query of the API call (ex. /api/v1/cluster/me)
+	Endpoint string
+}
+
+// Error returns the message provided by the Rubrik cluster or, when the API response did not include one, the HTTP status.
+func (e *APIError) Error() string {
+	if e.Message != "" {
+		return e.Message
+	}
+	return fmt.Sprintf("%d %s", e.StatusCode, http.StatusText(e.StatusCode))
+}
+
+// Is reports a 404 Not Found API response as ErrNotFound.
+func (e *APIError) Is(target error) bool {
+	return target == ErrNotFound && e.StatusCode == http.StatusNotFound
+}
+
+// sentinelError is an error with a descriptive message that matches one of the sentinel errors through errors.Is().
+type sentinelError struct {
+	sentinel error
+	message  string
+}
+
+func (e *sentinelError) Error() string {
+	return e.message
+}
+
+func (e *sentinelError) Unwrap() error {
+	return e.sentinel
+}
+
+// newError returns an error with the formatted message that matches the provided sentinel error through errors.Is().
+func newError(sentinel error, format string, a ...interface{}) error {
+	return &sentinelError{sentinel: sentinel, message: fmt.Sprintf(format, a...)}
+}
@@ -14,13 +14,89 @@
 package rubrikcdm_test
 
 import (
+	"context"
+	"errors"
+	"fmt"
 	"io/ioutil"
 	"log"
+	"log/slog"
 	"os"
+	"time"
 
 	"github.com/rubrikinc/rubrik-sdk-for-go/rubrikcdm"
+	"go.opentelemetry.io/otel"
 )
 
+func ExampleNewClient() {
+	credentials, err := rubrikcdm.ConnectEnv()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	caPEM, err := ioutil.ReadFile("/etc/ssl/rubrik-ca.pem")
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	rubrik, err := rubrikcdm.NewClient(credentials, rubrikcdm.WithCACertificates(caPEM), rubrikcdm.WithProxy("http://proxy.example.com:3128"))
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	clusterVersion, err := rubrik.ClusterVersion()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	fmt.Println(clusterVersion)
+}
+
+func ExampleClient_Close() {
+	credentials, err := rubrikcdm.ConnectEnv()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	rubrik, err := rubrikcdm.NewClient(credentials, rubrikcdm.WithInsecureSkipVerify())
+	if err != nil {
+		log.Fatal(err)
+	}
+	// Delete the session created on the Rubrik cluster
+	defer rubrik.Close()
+
+	clusterVersion, err := rubrik.ClusterVersion()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	fmt.Println(clusterVersion)
+}
+
+func ExampleWithRetryPolicy() {
+	credentials, err := rubrikcdm.ConnectEnv()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	// Keep retrying for several minutes while a node reboots during an upgrade
+	retryPolicy := rubrikcdm.DefaultRetryPolicy()
+	retryPolicy.MaxAttempts = 10
+	retryPolicy.MaxBackoff = time.Minute
+
+	rubrik, err := rubrikcdm.NewClient(credentials, rubrikcdm.WithInsecureSkipVerify(), rubrikcdm.WithRetryPolicy(retryPolicy))
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer rubrik.Close()
+
+	clusterVersion, err := rubrik.ClusterVersion()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	fmt.Println(clusterVersion)
+}
+
 func ExampleCredentials_ExportEC2Instance() {
 	rubrik, err := rubrikcdm.ConnectEnv()
 	if err != nil {
@@ -40,6 +116,7 @@ func ExampleCredentials_ExportEC2Instance() {
 	if err != nil {
 		log.Fatal(err)
 	}
+	fmt.Println(exportEC2)
 }
 
 func ExampleCredentials_ClusterVersion() {
@@ -52,6 +129,7 @@ func ExampleCredentials_ClusterVersion() {
 	if err != nil {
 		log.Fatal(err)
 	}
+	fmt.Println(clusterVersion)
 }
 
 func ExampleCredentials_BeginManagedVolumeSnapshot() {
@@ -66,6 +144,7 @@ func ExampleCredentials_BeginManagedVolumeSnapshot() {
 	if err != nil {
 		log.Fatal(err)
 	}
+	fmt.Println(beginMV)
 }
 
 func ExampleCredentials_PauseSnapshot() {
@@ -76,10 +155,11 @@ func ExampleCredentials_PauseSnapshot() {
 
 	vmName := "vm01"
 
-	pauseVM, err := rubrik.PauseSnapshot(vmName, "VMware")
+	pauseVM, err := rubrik.PauseSnapshot(vmName, rubrikcdm.ObjectTypeVMware)
 	if err != nil {
 		log.Fatal(err)
 	}
+	fmt.Println(pauseVM)
 }
 
 func ExampleCredentials_OnDemandSnapshotVM() {
@@ -91,10 +171,11 @@ func ExampleCredentials_OnDemandSnapshotVM() {
 	vmName := "ansible-node01"
 	sla := "current"
 
-	vmSnapshot, err := rubrik.OnDemandSnapshotVM(vmName, "VMware", sla)
+	vmSnapshot, err := rubrik.OnDemandSnapshotVM(vmName, rubrikcdm.ObjectTypeVMware, sla)
 	if err != nil {
 		log.Fatal(err)
 	}
+	fmt.Println(vmSnapshot)
 }
 
 func ExampleCredentials_OnDemandSnapshotPhysical() {
@@ -112,6 +193,7 @@ func ExampleCredentials_OnDemandSnapshotPhysical() {
 	if err != nil {
 		log.Fatal(err)
 	}
+	fmt.Println(hostSnapshot)
 }
 
 func ExampleCredentials_ResumeSnapshot() {
@@ -122,10 +204,60 @@ func ExampleCredentials_ResumeSnapshot() {
 
 	vmName := "vm01"
 
-	resumeVM, err := rubrik.ResumeSnapshot(vmName, "VMware")
+	resumeVM, err := rubrik.ResumeSnapshot(vmName, rubrikcdm.ObjectTypeVMware)
+	if err != nil {
+		log.Fatal(err)
+	}
+	fmt.Println(resumeVM)
+}
+
+func ExampleCredentials_CreateSLA() {
+	rubrik, err := rubrikcdm.ConnectEnv()
 	if err != nil {
 		log.Fatal(err)
 	}
+
+	sla := rubrikcdm.SLADomain{
+		Name: "Gold",
+		Frequencies: rubrikcdm.SLAFrequencies{
+			Hourly: &rubrikcdm.SLAFrequency{Frequency: 4, Retention: 24},
+			Daily:  &rubrikcdm.SLAFrequency{Frequency: 1, Retention: 30},
+			Weekly: &rubrikcdm.SLAFrequency{Frequency: 1, Retention: 12, DayOfWeek: "Saturday"},
+		},
+		// Only take snapshots between 10 PM and 6 AM
+		AllowedBackupWindows: []rubrikcdm.SLABackupWindow{
+			{StartTimeAttributes: rubrikcdm.SLAStartTime{Hour: 22}, DurationInHours: 8},
+		},
+	}
+
+	createSLA, err := rubrik.CreateSLA(sla)
+	if err != nil && errors.Is(err, rubrikcdm.ErrNoChangeRequired) == false {
+		log.Fatal(err)
+	}
+
+	fmt.Println(createSLA)
+}
+
+func ExampleCredentials_UpdateSLA() {
+	rubrik, err := rubrikcdm.ConnectEnv()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	sla, err := rubrik.GetSLA("Gold")
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	// Keep the daily snapshots for 60 days instead of 30
+	sla.Frequencies.Daily.Retention = 60
+
+	updateSLA, err := rubrik.UpdateSLA("Gold", *sla)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	fmt.Println(updateSLA)
 }
 
 func ExampleCredentials_GetSLAObjects() {
@@ -136,10 +268,11 @@ func ExampleCredentials_GetSLAObjects() {
 
 	slaName := "Gold"
 
-	getObjSLA, err := rubrik.GetSLAObjects(slaName, "VMware")
+	getObjSLA, err := rubrik.GetSLAObjects(slaName, rubrikcdm.ObjectTypeVMware)
 	if err != nil {
 		log.Fatal(err)
 	}
+	fmt.Println(getObjSLA)
 
 }
 
@@ -156,6 +289,7 @@ func ExampleCredentials_EndManagedVolumeSnapshot() {
 	if err != nil {
 		log.Fatal(err)
 	}
+	fmt.Println(endMV)
 }
 
 func ExampleCredentials_AssignSLA() {
@@ -171,6 +305,48 @@ func ExampleCredentials_AssignSLA() {
 	if err != nil {
 		log.Fatal(err)
 	}
+	fmt.Println(assignSLA)
+}
+
+func ExampleCredentials_AssignSLABulk() {
+	rubrik, err := rubrikcdm.ConnectEnv()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	objects := []rubrikcdm.ObjectQuery{
+		{Type: rubrikcdm.ObjectTypeVMware, Name: "vm01"},
+		{Type: rubrikcdm.ObjectTypeMSSQLDB, Name: "AdventureWorks", Hostname: "sql01.example.com", Instance: "MSSQLSERVER"},
+		{Type: rubrikcdm.ObjectTypeFileset, Name: "Linux Logs", Hostname: "web01.example.com"},
+	}
+
+	assignSLA, err := rubrik.AssignSLABulk(objects, "Gold")
+	if err != nil && errors.Is(err, rubrikcdm.ErrNoChangeRequired) == false {
+		log.Fatal(err)
+	}
+
+	fmt.Println(assignSLA)
+}
+
+func ExampleAPIError() {
+	rubrik, err := rubrikcdm.ConnectEnv()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	_, err = rubrik.AssignSLA("vm01", "vmware", "Gold")
+
+	var apiErr *rubrikcdm.APIError
+	switch {
+	case errors.Is(err, rubrikcdm.ErrNoChangeRequired):
+		fmt.Println("vm01 is already assigned to the Gold SLA Domain")
+	case errors.Is(err, rubrikcdm.ErrNotFound):
+		fmt.Println("vm01 or the Gold SLA Domain does not exist")
+	case errors.As(err, &apiErr):
+		log.Fatalf("%s %s returned %d (%s): %s", apiErr.Method, apiErr.Endpoint, apiErr.StatusCode, apiErr.ErrorType, apiErr.Message)
+	case err != nil:
+		log.Fatal(err)
+	}
 }
 
 func ExampleCredentials_ConfigureTimezone() {
@@ -180,6 +356,7 @@ func ExampleCredentials_ConfigureTimezone() {
 	if err != nil {
 		log.Fatal(err)
 	}
+	fmt.Println(confTimezone)
 }
 
 func ExampleCredentials_ConfigureVLAN() {
@@ -201,6 +378,7 @@ func ExampleCredentials_ConfigureVLAN() {
 	if err != nil {
 		log.Fatal(err)
 	}
+	fmt.Println(configVLAN)
 }
 
 func ExampleCredentials_AddvCenter() {
@@ -218,6 +396,7 @@ func ExampleCredentials_AddvCenter() {
 	if err != nil {
 		log.Fatal(err)
 	}
+	fmt.Println(addVcenter)
 }
 
 func ExampleCredentials_AddvCenterWithCert() {
@@ -237,6 +416,7 @@ func ExampleCredentials_AddvCenterWithCert() {
 	if err != nil {
 		log.Fatal(err)
 	}
+	fmt.Println(addVcenterWithCert)
 }
 
 func ExampleCredentials_ConfigureSMTPSettings() {
@@ -256,6 +436,7 @@ func ExampleCredentials_ConfigureSMTPSettings() {
 	if err != nil {
 		log.Fatal(err)
 	}
+	fmt.Println(smtpConfig)
 }
 
 func ExampleCredentials_ConfigureSearchDomain() {
@@ -270,6 +451,7 @@ func ExampleCredentials_ConfigureSearchDomain() {
 	if err != nil {
 		log.Fatal(err)
 	}
+	fmt.Println(searchDomainConfig)
 
 }
 
@@ -286,6 +468,205 @@ func ExampleCredentials_ObjectID() {
 	if err != nil {
 		log.Fatal(err)
 	}
+	fmt.Println(slaID)
+}
+
+func ExampleCredentials_FindObject() {
+	rubrik, err := rubrikcdm.ConnectEnv()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	query := rubrikcdm.ObjectQuery{
+		Type:     "mssqlDB",
+		Name:     "AdventureWorks",
+		Hostname: "sql01.example.com",
+		Instance: "MSSQLSERVER",
+	}
+
+	dbID, err := rubrik.FindObject(query)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	fmt.Println(dbID)
+}
+
+func ExampleCredentials_SelectSnapshot() {
+	rubrik, err := rubrikcdm.ConnectEnv()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	vmID, err := rubrik.ObjectID("ubuntu-01", rubrikcdm.ObjectTypeVMware, 15)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	// The most recent snapshot taken before yesterday that has been uploaded to an archival location
+	before := time.Now().AddDate(0, 0, -1)
+	snapshot, err := rubrik.SelectSnapshot(rubrikcdm.ObjectTypeVMware, vmID, rubrikcdm.LatestBefore(before).Where(rubrikcdm.Archived))
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	fmt.Println(snapshot.ID, snapshot.Date)
+}
+
+func ExampleParseDateTime() {
+	location, err := time.LoadLocation("America/Los_Angeles")
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	for _, dateTime := range []string{"2019-04-10T00:56:00Z", "2019-04-09 17:56", "04-09-2019 05:56 PM"} {
+		snapshotDateTime, err := rubrikcdm.ParseDateTime(dateTime, location)
+		if err != nil {
+			log.Fatal(err)
+		}
+		fmt.Println(snapshotDateTime.UTC().Format(time.RFC3339))
+	}
+
+	// Output:
+	// 2019-04-10T00:56:00Z
+	// 2019-04-10T00:56:00Z
+	// 2019-04-10T00:56:00Z
+}
+
+func ExampleCredentials_RecoverFileDownloadAt() {
+	rubrik, err := rubrikcdm.ConnectEnv()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	// The snapshot taken at 11 PM yesterday in the time zone of the Rubrik cluster
+	dateTime, err := rubrik.ClusterDateTime("yesterday 23:00")
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	job, err := rubrik.RecoverFileDownloadAt("centos-01", "etc", "Linux", "/etc/hosts", dateTime)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	fmt.Println(job.URL())
+}
+
+func ExampleCredentials_LiveMountVM() {
+	rubrik, err := rubrikcdm.ConnectEnv()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	options := rubrikcdm.VMRecoveryOptions{
+		VMName:         "ubuntu-01-restore-test",
+		Host:           "esxi01.example.com",
+		DisableNetwork: true,
+		PowerOn:        true,
+	}
+
+	recovery, err := rubrik.LiveMountVM("ubuntu-01", rubrikcdm.LatestBefore(time.Now().Add(-24*time.Hour)), options)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	if _, err := recovery.Wait(); err != nil {
+		log.Fatal(err)
+	}
+
+	// Remove the Live Mount once the restore test is complete
+	unmount, err := rubrik.UnmountVM(recovery.MountID(), false)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	if _, err := unmount.Wait(); err != nil {
+		log.Fatal(err)
+	}
+}
+
+func ExampleCredentials_ExportVM() {
+	rubrik, err := rubrikcdm.ConnectEnv()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	// Connect the new VM to the isolated recovery network instead of the production network of ubuntu-01
+	options := rubrikcdm.VMRecoveryOptions{
+		VMName:    "ubuntu-01-recovered",
+		Host:      "esxi01.example.com",
+		Datastore: "datastore01",
+		NetworkBindings: []rubrikcdm.VMNetworkBinding{
+			{NetworkAdapter: "Network adapter 1", Network: "recovery-pg"},
+		},
+	}
+
+	recovery, err := rubrik.ExportVM("ubuntu-01", rubrikcdm.Latest(), options)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	if _, err := recovery.Wait(); err != nil {
+		log.Fatal(err)
+	}
+}
+
+func ExampleCredentials_RestoreFiles() {
+	rubrik, err := rubrikcdm.ConnectEnv()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	fileset := rubrikcdm.ObjectQuery{Type: rubrikcdm.ObjectTypeFileset, Name: "etc", Hostname: "centos-01"}
+
+	// Restore the files to /tmp/restore on another host without replacing the files that already exist
+	options := rubrikcdm.FileRestoreOptions{
+		Host:           "centos-02",
+		Path:           "/tmp/restore",
+		ConflictPolicy: rubrikcdm.FileConflictIgnore,
+	}
+
+	job, err := rubrik.RestoreFiles(fileset, rubrikcdm.Latest(), []string{"/etc/hosts", "/etc/fstab"}, options)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	if _, err := job.Wait(); err != nil {
+		log.Fatal(err)
+	}
+}
+
+func ExampleCredentials_DownloadRecoveredFiles() {
+	rubrik, err := rubrikcdm.ConnectEnv()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	job, err := rubrik.RecoverFileDownload("centos-01", "etc", "Linux", "/etc/hosts", "yesterday 23:00")
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	// Append to the file left by a previous interrupted download, if any
+	file, err := os.OpenFile("hosts.zip", os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer file.Close()
+
+	info, err := file.Stat()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	ctx := rubrikcdm.WithDownloadProgress(context.Background(), func(written, total int64) {
+		fmt.Printf("%d/%d bytes\n", written, total)
+	})
+
+	if _, err := rubrik.DownloadRecoveredFilesContext(ctx, job, file, info.Size()); err != nil {
+		log.Fatal(err)
+	}
 }
 
 func ExampleCredentials_Bootstrap() {
@@ -318,6 +699,7 @@ func ExampleCredentials_Bootstrap() {
 	if err != nil {
 		log.Fatal(err)
 	}
+	fmt.Println(bootstrap)
 }
 
 func ExampleCredentials_Bootstrap_with_Secure_NTP() {
@@ -356,10 +738,11 @@ func ExampleCredentials_Bootstrap_with_Secure_NTP() {
 	if err != nil {
 		log.Fatal(err)
 	}
+	fmt.Println(bootstrap)
 
 }
 
-func ExampleCredentials_BootstrapAws() {
+func ExampleCredentials_BootstrapCcesAws() {
 
 	bootstrapNode := "192.168.102.100"
 	rubrik := rubrikcdm.Connect(bootstrapNode, "", "")
@@ -393,7 +776,7 @@ func ExampleCredentials_BootstrapAws() {
 
 }
 
-func ExampleCredentials_BootstrapAzure() {
+func ExampleCredentials_BootstrapCcesAzure() {
 
 	bootstrapNode := "192.168.103.100"
 	rubrik := rubrikcdm.Connect(bootstrapNode, "", "")
@@ -437,6 +820,7 @@ func ExampleCredentials_ConfigureDNSServers() {
 	if err != nil {
 		log.Fatal(err)
 	}
+	fmt.Println(dnsConfig)
 }
 
 func ExampleCredentials_ConfigureSyslog() {
@@ -447,12 +831,13 @@ func ExampleCredentials_ConfigureSyslog() {
 
 	syslogIP := "192.168.100.7"
 	syslogProtocol := "UDP"
-	syslogPort := 514
+	syslogPort := 514.0
 
 	syslog, err := rubrik.ConfigureSyslog(syslogIP, syslogProtocol, syslogPort)
 	if err != nil {
 		log.Fatal(err)
 	}
+	fmt.Println(syslog)
 }
 
 func ExampleCredentials_RegisterCluster() {
@@ -468,6 +853,7 @@ func ExampleCredentials_RegisterCluster() {
 	if err != nil {
 		log.Fatal(err)
 	}
+	fmt.Println(register)
 }
 
 // NOTE: This code is broken for releases of Rubrik CDM v5.0 and later.
@@ -483,6 +869,7 @@ func ExampleCredentials_ConfigureNTP() {
 	if err != nil {
 		log.Fatal(err)
 	}
+	fmt.Println(ntp)
 }
 
 func ExampleCredentials_ClusterNodeIP() {
@@ -495,6 +882,7 @@ func ExampleCredentials_ClusterNodeIP() {
 	if err != nil {
 		log.Fatal(err)
 	}
+	fmt.Println(clusterVersion)
 }
 
 func ExampleCredentials_EndUserAuthorization() {
@@ -506,10 +894,11 @@ func ExampleCredentials_EndUserAuthorization() {
 	vmName := "vm01"
 	endUser := "user01"
 
-	endUserAuth, err := rubrik.EndUserAuthorization(vmName, endUser, "VMware")
+	endUserAuth, err := rubrik.EndUserAuthorization(vmName, endUser, rubrikcdm.ObjectTypeVMware)
 	if err != nil {
 		log.Fatal(err)
 	}
+	fmt.Println(endUserAuth)
 }
 
 func ExampleCredentials_ClusterVersionCheck() {
@@ -534,6 +923,294 @@ func ExampleCredentials_Get() {
 	if err != nil {
 		log.Fatal(err)
 	}
+	fmt.Println(clusterInfo)
+}
+
+func ExampleWithNodes() {
+	credentials, err := rubrikcdm.ConnectEnv()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	// Send requests to the other nodes while the node in the rubrik_cdm_node_ip environment variable is unavailable
+	rubrik, err := rubrikcdm.NewClient(credentials, rubrikcdm.WithInsecureSkipVerify(), rubrikcdm.WithNodes("192.168.1.11", "192.168.1.12", "192.168.1.13"))
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer rubrik.Close()
+
+	clusterVersion, err := rubrik.ClusterVersion()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	fmt.Println(clusterVersion)
+}
+
+func ExampleGetInto() {
+	rubrik, err := rubrikcdm.ConnectEnv()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	clusterVersion, err := rubrikcdm.GetInto[rubrikcdm.ClusterVersion](rubrik, "v1", "/cluster/me/version")
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	fmt.Println(clusterVersion.Version)
+}
+
+func ExampleNewPager() {
+	rubrik, err := rubrikcdm.ConnectEnv()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	pager := rubrikcdm.NewPager[map[string]interface{}](rubrik, "v1", "/vmware/vm?is_relic=false", 0)
+	for pager.Next() {
+		fmt.Println(pager.Item()["name"])
+	}
+	if err := pager.Err(); err != nil {
+		log.Fatal(err)
+	}
+}
+
+func ExampleListAll() {
+	rubrik, err := rubrikcdm.ConnectEnv()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	slaDomains, err := rubrikcdm.ListAll[map[string]interface{}](rubrik, "v1", "/sla_domain?primary_cluster_id=local")
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	fmt.Println(len(slaDomains))
+}
+
+func ExampleWithLogger() {
+	credentials, err := rubrikcdm.ConnectEnv()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	logger := slog.New(slog.NewJSONHandler(os.Stderr, &slog.HandlerOptions{Level: slog.LevelDebug}))
+
+	// Log every request along with its redacted headers and bodies
+	rubrik, err := rubrikcdm.NewClient(credentials, rubrikcdm.WithInsecureSkipVerify(), rubrikcdm.WithLogger(logger), rubrikcdm.WithBodyLogging())
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer rubrik.Close()
+
+	clusterVersion, err := rubrik.ClusterVersion()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	fmt.Println(clusterVersion)
+}
+
+func ExampleWithTracerProvider() {
+	credentials, err := rubrikcdm.ConnectEnv()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	// Use the TracerProvider and MeterProvider configured by the application
+	tracerProvider := otel.GetTracerProvider()
+	meterProvider := otel.GetMeterProvider()
+
+	rubrik, err := rubrikcdm.NewClient(credentials, rubrikcdm.WithInsecureSkipVerify(), rubrikcdm.WithTracerProvider(tracerProvider), rubrikcdm.WithMeterProvider(meterProvider))
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer rubrik.Close()
+
+	snapshot, err := rubrik.OnDemandSnapshotVM("ansible-node01", "vmware", "Gold")
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	fmt.Println(snapshot)
+}
+
+func ExampleWithCassette() {
+	credentials, err := rubrikcdm.ConnectEnv()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	// Record the API calls against the lab Rubrik cluster once with rubrikcdm.CassetteRecord and replay them in CI
+	rubrik, err := rubrikcdm.NewClient(credentials, rubrikcdm.WithInsecureSkipVerify(), rubrikcdm.WithCassette("testdata/assign_sla.json", rubrikcdm.CassetteReplay))
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer rubrik.Close()
+
+	_, err = rubrik.AssignSLA("ubuntu-01", "vmware", "Gold")
+	if errors.Is(err, rubrikcdm.ErrNoRecordedInteraction) {
+		log.Fatal("The cassette must be recorded again: ", err)
+	}
+}
+
+func ExampleWaitEach() {
+	rubrik, err := rubrikcdm.ConnectEnv()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	var jobs []*rubrikcdm.Job
+	for _, vmName := range []string{"vm01", "vm02", "vm03"} {
+		job, err := rubrik.OnDemandSnapshotVM(vmName, "vmware", "current")
+		if err != nil {
+			log.Fatal(err)
+		}
+		jobs = append(jobs, job)
+	}
+
+	// Send at most 5 job status requests at the same time and report each snapshot as soon as it finishes
+	for result := range rubrikcdm.WaitEach(jobs, 5) {
+		if result.Err != nil {
+			fmt.Printf("%s failed: %s\n", result.Job.ID(), result.Err)
+			continue
+		}
+		fmt.Printf("%s succeeded at %s\n", result.Job.ID(), result.Status.EndTime)
+	}
+}
+
+func ExampleCredentials_ClusterCDMVersion() {
+	rubrik, err := rubrikcdm.ConnectEnv()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	version, err := rubrik.ClusterCDMVersion()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	minimum, err := rubrikcdm.ParseCDMVersion("5.3.2")
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	if version.AtLeast(minimum) == false {
+		log.Fatalf("CDM %s is required, the Rubrik cluster is running %s", minimum, version)
+	}
+
+	supported, err := rubrik.Supports(rubrikcdm.CapabilityEC2InstanceExport)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	fmt.Println(supported)
+}
+
+func ExampleWithObjectCache() {
+	credentials, err := rubrikcdm.ConnectEnv()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	rubrik, err := rubrikcdm.NewClient(credentials, rubrikcdm.WithInsecureSkipVerify(), rubrikcdm.WithObjectCache(10*time.Minute))
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer rubrik.Close()
+
+	// The "Gold" SLA Domain ID is only looked up once
+	for _, vmName := range []string{"vm01", "vm02", "vm03"} {
+		_, err := rubrik.AssignSLA(vmName, "vmware", "Gold")
+		if err != nil && errors.Is(err, rubrikcdm.ErrNoChangeRequired) == false {
+			log.Fatal(err)
+		}
+	}
+
+	// The VM was replaced outside of the Client
+	rubrik.InvalidateObjectID("vm01", "vmware")
+}
+
+func ExampleCredentials_GetContext() {
+	rubrik, err := rubrikcdm.ConnectEnv()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	clusterInfo, err := rubrik.GetContext(ctx, "v1", "/cluster/me")
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	fmt.Println(clusterInfo)
+}
+
+func ExampleCredentials_JobStatusContext() {
+	rubrik, err := rubrikcdm.ConnectEnv()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	// Stop waiting for the on-demand snapshot after one hour
+	ctx, cancel := context.WithTimeout(context.Background(), time.Hour)
+	defer cancel()
+
+	job, err := rubrik.OnDemandSnapshotVMContext(ctx, "vm01", "vmware", "current")
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	status, err := rubrik.JobStatusContext(ctx, job.URL())
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	fmt.Println(status)
+}
+
+func ExampleJob_WaitContext() {
+	credentials, err := rubrikcdm.ConnectEnv()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	// Poll every 2 seconds at first, then back off up to once a minute
+	pollPolicy := rubrikcdm.PollPolicy{
+		InitialInterval: 2 * time.Second,
+		MaxInterval:     time.Minute,
+		Multiplier:      2,
+	}
+
+	rubrik, err := rubrikcdm.NewClient(credentials, rubrikcdm.WithInsecureSkipVerify(), rubrikcdm.WithPollPolicy(pollPolicy))
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer rubrik.Close()
+
+	ctx := rubrikcdm.WithJobProgress(context.Background(), func(job *rubrikcdm.Job, status rubrikcdm.JobStatus) {
+		fmt.Printf("%s: %s %.0f%%\n", job.ID(), status.Status, status.Progress)
+	})
+
+	job, err := rubrik.OnDemandSnapshotVMContext(ctx, "vm01", "vmware", "current")
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	status, err := job.WaitContext(ctx)
+	if errors.Is(err, rubrikcdm.ErrJobFailed) {
+		// The error includes the reason reported by the Rubrik cluster
+		fmt.Println(err)
+		return
+	} else if err != nil {
+		log.Fatal(err)
+	}
+
+	fmt.Println(status.EndTime)
 }
 
 func ExampleCredentials_Post() {
@@ -549,6 +1226,7 @@ func ExampleCredentials_Post() {
 	if err != nil {
 		log.Fatal(err)
 	}
+	fmt.Println(onDemandSnapshot)
 }
 
 func ExampleCredentials_Patch() {
@@ -564,6 +1242,7 @@ func ExampleCredentials_Patch() {
 	if err != nil {
 		log.Fatal(err)
 	}
+	fmt.Println(fileset)
 }
 
 func ExampleCredentials_Delete() {
@@ -576,6 +1255,7 @@ func ExampleCredentials_Delete() {
 	if err != nil {
 		log.Fatal(err)
 	}
+	fmt.Println(deleteSLA)
 }
 
 func ExampleCredentials_AddAWSNativeAccount() {
@@ -600,6 +1280,7 @@ func ExampleCredentials_AddAWSNativeAccount() {
 	if err != nil {
 		log.Fatal(err)
 	}
+	fmt.Println(addAWSNative)
 }
 
 func ExampleCredentials_RefreshvCenter() {
@@ -615,6 +1296,7 @@ func ExampleCredentials_RefreshvCenter() {
 		log.Fatal(err)
 
 	}
+	fmt.Println(refresh)
 }
 
 func ExampleCredentials_RemoveAWSAccount() {
@@ -630,6 +1312,7 @@ func ExampleCredentials_RemoveAWSAccount() {
 	if err != nil {
 		log.Fatal(err)
 	}
+	fmt.Println(removeAWSAccount)
 
 }
 
@@ -649,6 +1332,7 @@ func ExampleCredentials_UpdateAWSNativeAccount() {
 	if err != nil {
 		log.Fatal(err)
 	}
+	fmt.Println(updateAWSAccount)
 
 }
 
@@ -662,6 +1346,7 @@ func ExampleCredentials_CloudObjectStore() {
 	if err != nil {
 		log.Fatal(err)
 	}
+	fmt.Println(archiveLocations)
 
 }
 
@@ -677,6 +1362,7 @@ func ExampleCredentials_AWSAccountSummary() {
 	if err != nil {
 		log.Fatal(err)
 	}
+	fmt.Println(awsSummary)
 
 }
 
@@ -692,6 +1378,7 @@ func ExampleCredentials_RemoveArchiveLocation() {
 	if err != nil {
 		log.Fatal(err)
 	}
+	fmt.Println(removeArchive)
 
 }
 
@@ -711,6 +1398,7 @@ func ExampleCredentials_UpdateCloudArchiveLocation() {
 	if err != nil {
 		log.Fatal(err)
 	}
+	fmt.Println(updateArchive)
 
 }
 
@@ -733,6 +1421,7 @@ func ExampleCredentials_AWSS3CloudOutRSA() {
 	if err != nil {
 		log.Fatal(err)
 	}
+	fmt.Println(awsCloudOut)
 }
 
 func ExampleCredentials_AWSS3CloudOutKMS() {
@@ -753,6 +1442,7 @@ func ExampleCredentials_AWSS3CloudOutKMS() {
 	if err != nil {
 		log.Fatal(err)
 	}
+	fmt.Println(awsCloudOut)
 }
 
 func ExampleCredentials_AWSS3CloudOn() {
@@ -770,6 +1460,7 @@ func ExampleCredentials_AWSS3CloudOn() {
 	if err != nil {
 		log.Fatal(err)
 	}
+	fmt.Println(awsCloudOn)
 }
 
 func ExampleCredentials_AzureCloudOut() {
@@ -790,6 +1481,7 @@ func ExampleCredentials_AzureCloudOut() {
 	if err != nil {
 		log.Fatal(err)
 	}
+	fmt.Println(azureCloudOut)
 }
 
 func ExampleCredentials_AzureCloudOn() {
@@ -814,6 +1506,7 @@ func ExampleCredentials_AzureCloudOn() {
 	if err != nil {
 		log.Fatal(err)
 	}
+	fmt.Println(azureCloudOn)
 
 }
 
@@ -833,4 +1526,5 @@ func ExampleCredentials_RecoverFileDownload() {
 	if err != nil {
 		log.Fatal(err)
 	}
+	fmt.Println(fileDownload)
 }
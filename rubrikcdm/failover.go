@@ -0,0 +1,226 @@
+// Copyright 2018 Rubrik, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License prop
+//  http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rubrikcdm
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// nodeUnhealthyPeriod is how long a node that failed to respond is skipped before it is probed again.
+const nodeUnhealthyPeriod = 30 * time.Second
+
+// nodePool tracks the health of the Rubrik cluster nodes used by a Client and selects the node for each request.
+type nodePool struct {
+	mu       sync.Mutex
+	nodes    []*node
+	next     int
+	discover bool
+	// discoveryState is 0 before the nodes are discovered, 1 while the discovery is in progress and 2 once complete
+	discoveryState int
+}
+
+type node struct {
+	address   string
+	unhealthy bool
+	// unhealthyUntil is when an unhealthy node is probed again
+	unhealthyUntil time.Time
+	probing        bool
+}
+
+// WithNodes adds the IP addresses (or hostnames) of the other nodes in the Rubrik cluster to the Client. Requests are
+// distributed across the healthy nodes in a round-robin fashion. A node that fails to respond is marked unhealthy and
+// the request is sent to the next node, including requests to job status URLs. Every 30 seconds, an unhealthy node is
+// probed in the background with GET /api/v1/cluster/me and only receives requests again once it responds.
+func WithNodes(nodeIPs ...string) ClientOption {
+	return func(config *clientConfig) error {
+		if len(nodeIPs) == 0 {
+			return errors.New("At least one node IP must be provided")
+		}
+		if config.nodes == nil {
+			config.nodes = &nodePool{}
+		}
+		for _, nodeIP := range nodeIPs {
+			config.nodes.add(nodeIP)
+		}
+		return nil
+	}
+}
+
+// WithNodeDiscovery discovers the IP address of every node in the Rubrik cluster through GET /internal/cluster/me/node
+// on the first request and distributes requests across them in the same way as WithNodes().
+func WithNodeDiscovery() ClientOption {
+	return func(config *clientConfig) error {
+		if config.nodes == nil {
+			config.nodes = &nodePool{}
+		}
+		config.nodes.discover = true
+		return nil
+	}
+}
+
+// seed makes the node provided to Connect() the first node of the pool.
+func (pool *nodePool) seed(address string) {
+	pool.add(address)
+	for i, n := range pool.nodes {
+		if n.address == address {
+			pool.nodes[0], pool.nodes[i] = pool.nodes[i], pool.nodes[0]
+		}
+	}
+}
+
+// add appends the node to the pool unless it is already present.
+func (pool *nodePool) add(address string) {
+	for _, n := range pool.nodes {
+		if n.address == address {
+			return
+		}
+	}
+	pool.nodes = append(pool.nodes, &node{address: address})
+}
+
+// selectNode returns the next healthy node along with the unhealthy nodes that are due to be probed. When every node is
+// unhealthy, the node that will be probed first is returned.
+func (pool *nodePool) selectNode() (string, []string) {
+	pool.mu.Lock()
+	defer pool.mu.Unlock()
+
+	now := time.Now()
+	var probes []string
+	for _, n := range pool.nodes {
+		if n.unhealthy && n.probing == false && now.After(n.unhealthyUntil) {
+			n.probing = true
+			probes = append(probes, n.address)
+		}
+	}
+
+	for i := 0; i < len(pool.nodes); i++ {
+		n := pool.nodes[(pool.next+i)%len(pool.nodes)]
+		if n.unhealthy == false {
+			pool.next = (pool.next + i + 1) % len(pool.nodes)
+			return n.address, probes
+		}
+	}
+
+	recovering := pool.nodes[0]
+	for _, n := range pool.nodes {
+		if n.unhealthyUntil.Before(recovering.unhealthyUntil) {
+			recovering = n
+		}
+	}
+	return recovering.address, probes
+}
+
+// markUnhealthy stops sending requests to the node until it responds to a probe.
+func (pool *nodePool) markUnhealthy(address string) {
+	pool.mu.Lock()
+	defer pool.mu.Unlock()
+
+	for _, n := range pool.nodes {
+		if n.address == address {
+			n.unhealthy = true
+			n.unhealthyUntil = time.Now().Add(nodeUnhealthyPeriod)
+			n.probing = false
+		}
+	}
+}
+
+// markHealthy sends requests to the node again.
+func (pool *nodePool) markHealthy(address string) {
+	pool.mu.Lock()
+	defer pool.mu.Unlock()
+
+	for _, n := range pool.nodes {
+		if n.address == address {
+			n.unhealthy = false
+			n.probing = false
+		}
+	}
+}
+
+// size returns the number of nodes in the pool.
+func (pool *nodePool) size() int {
+	pool.mu.Lock()
+	defer pool.mu.Unlock()
+
+	return len(pool.nodes)
+}
+
+// selectNode returns the node the next request should be sent to or an empty string when node failover is not configured.
+func (c *Credentials) selectNode(ctx context.Context, timeout int) string {
+
+	if c.config == nil || c.config.nodes == nil {
+		return ""
+	}
+
+	pool := c.config.nodes
+
+	pool.mu.Lock()
+	discover := pool.discover && pool.discoveryState == 0
+	if discover {
+		pool.discoveryState = 1
+	}
+	pool.mu.Unlock()
+
+	if discover {
+		nodeIPs, err := c.ClusterNodeIPContext(ctx, timeout)
+
+		pool.mu.Lock()
+		if err != nil {
+			// Try again on the next request
+			pool.discoveryState = 0
+		} else {
+			for _, nodeIP := range nodeIPs {
+				pool.add(nodeIP)
+			}
+			pool.discoveryState = 2
+		}
+		pool.mu.Unlock()
+	}
+
+	address, probes := pool.selectNode()
+	for _, probe := range probes {
+		go c.probeNode(probe, timeout)
+	}
+
+	return address
+}
+
+// probeNode sends GET /api/v1/cluster/me to an unhealthy node and marks it healthy when the node responds, even with an
+// error status, or unhealthy for another nodeUnhealthyPeriod otherwise.
+func (c *Credentials) probeNode(address string, timeout int) {
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(timeout)*time.Second)
+	defer cancel()
+
+	request, err := http.NewRequestWithContext(ctx, "GET", fmt.Sprintf("https://%s/api/v1/cluster/me", address), nil)
+	if err != nil {
+		c.config.nodes.markUnhealthy(address)
+		return
+	}
+	request.Header.Set("Accept", "application/json")
+	request.Header.Set("User-Agent", userAgent)
+
+	response, err := c.httpClient(timeout).Do(request)
+	if err != nil {
+		c.config.nodes.markUnhealthy(address)
+		return
+	}
+	response.Body.Close()
+	c.config.nodes.markHealthy(address)
+}
@@ -0,0 +1,95 @@
+// Copyright 2018 Rubrik, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License prop
+//  http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rubrikcdm_test
+
+import (
+	"net"
+	"strings"
+	"testing"
+
+	"github.com/rubrikinc/rubrik-sdk-for-go/rubrikcdm"
+	"github.com/rubrikinc/rubrik-sdk-for-go/rubrikcdm/rubrikcdmtest"
+)
+
+// downNode returns the address of a node that refuses every connection.
+func downNode(t *testing.T) string {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	address := listener.Addr().String()
+	listener.Close()
+	return address
+}
+
+func TestFailoverWithOneNodeDown(t *testing.T) {
+
+	server := rubrikcdmtest.NewServer()
+	defer server.Close()
+	server.AddVM("ubuntu-01")
+	server.AddSLADomain("Gold")
+
+	// The node provided to Connect() is down, every request must be sent to the node provided to WithNodes()
+	credentials := rubrikcdm.Connect(downNode(t), "admin", "password")
+	rubrik, err := rubrikcdm.NewClient(credentials, rubrikcdm.WithHTTPClient(server.Client()), rubrikcdm.WithNodes(server.NodeIP()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer rubrik.Close()
+
+	for i := 0; i < 3; i++ {
+		if _, err := rubrik.ClusterVersion(); err != nil {
+			t.Fatalf("ClusterVersion() returned an error with one node down: %v", err)
+		}
+	}
+
+	// A POST is sent to the next node because the refused connection never reached the Rubrik cluster
+	if _, err := rubrik.AssignSLA("ubuntu-01", rubrikcdm.ObjectTypeVMware, "Gold"); err != nil {
+		t.Fatalf("AssignSLA() returned an error with one node down: %v", err)
+	}
+	vm, _ := server.VM("ubuntu-01")
+	if vm.EffectiveSLADomainID != server.SLADomainID("Gold") {
+		t.Errorf("AssignSLA() did not assign the VM to the Gold SLA Domain")
+	}
+
+	// Job status URLs that point to the node that is down are sent to the healthy node
+	job, err := rubrik.OnDemandSnapshotVM("ubuntu-01", rubrikcdm.ObjectTypeVMware, "current")
+	if err != nil {
+		t.Fatal(err)
+	}
+	jobStatusURL := strings.Replace(job.URL(), server.NodeIP(), credentials.NodeIP, 1)
+	if _, err := rubrik.NewJob(jobStatusURL).Wait(); err != nil {
+		t.Errorf("Wait() returned an error for a job status URL of the node that is down: %v", err)
+	}
+}
+
+func TestFailoverWithEveryNodeDown(t *testing.T) {
+
+	server := rubrikcdmtest.NewServer()
+	defer server.Close()
+
+	retryPolicy := rubrikcdm.RetryPolicy{MaxAttempts: 1}
+	rubrik, err := rubrikcdm.NewClient(rubrikcdm.ConnectAPIToken(downNode(t), "token"),
+		rubrikcdm.WithHTTPClient(server.Client()), rubrikcdm.WithNodes(downNode(t)), rubrikcdm.WithRetryPolicy(retryPolicy))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := rubrik.ClusterVersion(); err == nil {
+		t.Error("ClusterVersion() succeeded with every node down")
+	}
+	if len(server.Requests()) != 0 {
+		t.Errorf("the Rubrik cluster received %v", server.Requests())
+	}
+}
@@ -0,0 +1,296 @@
+// Copyright 2018 Rubrik, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License prop
+//  http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rubrikcdm
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"path"
+	"sync"
+	"time"
+
+	"github.com/mitchellh/mapstructure"
+)
+
+// ErrJobFailed is returned when a job finishes with any status other than SUCCEEDED (ex. FAILED or CANCELED). The error
+// message includes the "error.message" field reported by the Rubrik cluster.
+var ErrJobFailed = errors.New("Job failed")
+
+// PollPolicy controls how often the status of a job is requested while waiting for it to complete.
+type PollPolicy struct {
+	// InitialInterval is the wait between the first two status requests.
+	InitialInterval time.Duration
+	// MaxInterval caps the wait between two status requests.
+	MaxInterval time.Duration
+	// Multiplier increases the wait after every status request. A value of 1 or less polls at a fixed interval.
+	Multiplier float64
+}
+
+// DefaultPollPolicy returns the PollPolicy used when WithPollPolicy() is not provided: the status is requested every 10 seconds.
+func DefaultPollPolicy() PollPolicy {
+	return PollPolicy{
+		InitialInterval: 10 * time.Second,
+		MaxInterval:     10 * time.Second,
+		Multiplier:      1,
+	}
+}
+
+// WithPollPolicy replaces the DefaultPollPolicy() used by the Client to wait for jobs.
+func WithPollPolicy(policy PollPolicy) ClientOption {
+	return func(config *clientConfig) error {
+		if policy.InitialInterval <= 0 {
+			return errors.New("The PollPolicy 'InitialInterval' must be greater than 0")
+		}
+		if policy.MaxInterval < policy.InitialInterval {
+			return errors.New("The PollPolicy 'MaxInterval' must not be lower than 'InitialInterval'")
+		}
+		config.pollPolicy = &policy
+		return nil
+	}
+}
+
+// pollPolicy returns the PollPolicy used to wait for jobs.
+func (c *Credentials) pollPolicy() PollPolicy {
+	if c.config == nil || c.config.pollPolicy == nil {
+		return DefaultPollPolicy()
+	}
+	return *c.config.pollPolicy
+}
+
+// next returns the wait following the provided interval.
+func (policy PollPolicy) next(interval time.Duration) time.Duration {
+	if policy.Multiplier > 1 {
+		interval = time.Duration(float64(interval) * policy.Multiplier)
+	}
+	if interval > policy.MaxInterval {
+		interval = policy.MaxInterval
+	}
+	return interval
+}
+
+// ProgressFunc is called with the status of a job every time it is requested from the Rubrik cluster.
+type ProgressFunc func(job *Job, status JobStatus)
+
+type progressKey struct{}
+
+// WithJobProgress returns a copy of the context that calls "fn" every time the status of a job is requested with it,
+// including the jobs monitored by helper functions such as RefreshvCenterContext(), ex:
+//
+//	ctx = rubrikcdm.WithJobProgress(ctx, func(job *rubrikcdm.Job, status rubrikcdm.JobStatus) {
+//		fmt.Printf("%s: %s %.0f%%\n", job.ID(), status.Status, status.Progress)
+//	})
+//	job, err := rubrik.OnDemandSnapshotVMContext(ctx, "vm01", "vmware", "current")
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+//	_, err = job.WaitContext(ctx)
+func WithJobProgress(ctx context.Context, fn ProgressFunc) context.Context {
+	return context.WithValue(ctx, progressKey{}, fn)
+}
+
+// Job monitors an asynchronous operation (ex. an on-demand snapshot) through its job status URL. Jobs are returned by
+// the helper functions that start an asynchronous operation and can be created for any job status URL with NewJob().
+// A Job is safe for concurrent use.
+type Job struct {
+	credentials *Credentials
+	url         string
+	timeout     int
+
+	mu          sync.Mutex
+	status      JobStatus
+	apiRequest  *http.Response
+	apiResponse []byte
+}
+
+// NewJob returns a Job for the provided job status URL (ex. the "href" of the first link returned when an on-demand
+// snapshot is started). The status of the job is not requested until Poll() or Wait() is called.
+func (c *Credentials) NewJob(jobStatusURL string, timeout ...int) *Job {
+	return &Job{
+		credentials: c,
+		url:         jobStatusURL,
+		timeout:     httpTimeout(timeout),
+	}
+}
+
+// newJob returns a Job for the API response of a request that started an asynchronous operation.
+func (c *Credentials) newJob(apiResponse interface{}, timeout int) (*Job, error) {
+
+	// Convert the API Response (map[string]interface{}) to a struct
+	var status JobStatus
+	if err := mapstructure.Decode(apiResponse, &status); err != nil {
+		return nil, err
+	}
+
+	if len(status.Links) == 0 {
+		return nil, errors.New("The API response did not contain a job status URL")
+	}
+
+	job := c.NewJob(status.Links[0].Href, timeout)
+	job.status = status
+
+	return job, nil
+}
+
+// URL returns the job status URL.
+func (j *Job) URL() string {
+	return j.url
+}
+
+// ID returns the ID of the job reported by the Rubrik cluster or, before the status has been requested, the last
+// segment of the job status URL.
+func (j *Job) ID() string {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	if j.status.ID != "" {
+		return j.status.ID
+	}
+
+	if parsedURL, err := url.Parse(j.url); err == nil {
+		return path.Base(parsedURL.Path)
+	}
+	return ""
+}
+
+// Status returns the last status of the job received from the Rubrik cluster without sending a request.
+func (j *Job) Status() JobStatus {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	return j.status
+}
+
+// Response returns the full API response of the last status request, or nil when the status has not been requested.
+func (j *Job) Response() interface{} {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	if j.apiRequest == nil {
+		return nil
+	}
+
+	response, _ := parseAPIResponse(j.apiRequest, j.apiResponse)
+	return response
+}
+
+// Done returns true once the job reached a final status, whether it succeeded or not.
+func (j *Job) Done() bool {
+	return jobDone(j.Status().Status)
+}
+
+// Poll requests the current status of the job. A job that finished with any status other than SUCCEEDED returns an
+// error that matches ErrJobFailed.
+func (j *Job) Poll() (JobStatus, error) {
+	return j.PollContext(context.Background())
+}
+
+// PollContext is the same as Poll with the addition of a context.Context that is used to cancel in-flight requests.
+func (j *Job) PollContext(ctx context.Context) (JobStatus, error) {
+
+	// Dummy place holder values to pass validation
+	apiRequest, apiResponse, err := j.credentials.apiCall(ctx, "JOB_STATUS", "v1", "/placeholder", j.url, j.timeout)
+	if err != nil {
+		return JobStatus{}, err
+	}
+
+	status, err := decodeInto[JobStatus](apiRequest, apiResponse)
+	if err != nil {
+		return JobStatus{}, err
+	}
+
+	if status.Status == "" {
+		return JobStatus{}, fmt.Errorf("The API response of %s did not contain the status of the job", j.url)
+	}
+
+	j.mu.Lock()
+	j.status = status
+	j.apiRequest = apiRequest
+	j.apiResponse = apiResponse
+	j.mu.Unlock()
+
+	if progress, ok := ctx.Value(progressKey{}).(ProgressFunc); ok && progress != nil {
+		progress(j, status)
+	}
+
+	if jobDone(status.Status) && status.Status != "SUCCEEDED" {
+		return status, j.failure(status)
+	}
+
+	return status, nil
+}
+
+// Wait polls the status of the job according to the PollPolicy of the Client until the job finishes and returns its
+// final status. A job that finished with any status other than SUCCEEDED returns an error that matches ErrJobFailed.
+func (j *Job) Wait() (JobStatus, error) {
+	return j.WaitContext(context.Background())
+}
+
+// WaitContext is the same as Wait with the addition of a context.Context that is used to cancel in-flight requests and any polling.
+func (j *Job) WaitContext(ctx context.Context) (JobStatus, error) {
+
+	ctx, span := j.credentials.startSpan(ctx, "Job.Wait")
+	defer span.End()
+
+	policy := j.credentials.pollPolicy()
+	interval := policy.InitialInterval
+
+	for {
+		status, err := j.PollContext(ctx)
+		if err != nil || jobDone(status.Status) {
+			return status, err
+		}
+
+		if err := sleepContext(ctx, interval); err != nil {
+			return status, err
+		}
+		interval = policy.next(interval)
+	}
+}
+
+// Cancel requests the cancellation of the job through POST /internal/job/instance/{id}/cancel. The job reports the
+// CANCELING status until the Rubrik cluster has stopped it, use Wait() to wait for the CANCELED status.
+func (j *Job) Cancel() error {
+	return j.CancelContext(context.Background())
+}
+
+// CancelContext is the same as Cancel with the addition of a context.Context that is used to cancel in-flight requests.
+func (j *Job) CancelContext(ctx context.Context) error {
+
+	ctx, span := j.credentials.startSpan(ctx, "Job.Cancel")
+	defer span.End()
+
+	_, err := j.credentials.PostContext(ctx, "internal", fmt.Sprintf("/job/instance/%s/cancel", url.PathEscape(j.ID())), map[string]interface{}{}, j.timeout)
+
+	return err
+}
+
+// failure returns the error of a job that did not succeed.
+func (j *Job) failure(status JobStatus) error {
+	if status.Error.Message != "" {
+		return newError(ErrJobFailed, "The job %s finished with the %s status: %s", status.ID, status.Status, status.Error.Message)
+	}
+	return newError(ErrJobFailed, "The job %s finished with the %s status", status.ID, status.Status)
+}
+
+// jobDone returns true when the job status is final.
+func jobDone(status string) bool {
+	switch status {
+	case "", "QUEUED", "ACQUIRING", "RUNNING", "FINISHING", "TO_CANCEL", "CANCELING":
+		return false
+	}
+	return true
+}
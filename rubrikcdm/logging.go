@@ -0,0 +1,168 @@
+// Copyright 2018 Rubrik, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License prop
+//  http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rubrikcdm
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// redacted replaces the value of every secret written to the log.
+const redacted = "[REDACTED]"
+
+// maxLoggedBodySize is the number of bytes of a request or response body written to the log.
+const maxLoggedBodySize = 64 * 1024
+
+// redactedFields are the JSON fields, compared in lowercase, whose value is never written to the log.
+var redactedFields = map[string]bool{
+	"password":       true,
+	"secretkey":      true,
+	"smtppassword":   true,
+	"pemfilecontent": true,
+	"adminuserinfo":  true,
+	"token":          true,
+}
+
+// redactedHeaders are the HTTP headers whose value is never written to the log.
+var redactedHeaders = map[string]bool{
+	"Authorization": true,
+	"Cookie":        true,
+	"Set-Cookie":    true,
+}
+
+// WithLogger logs every request sent to the Rubrik cluster through the provided slog.Logger. Each request is logged at
+// the Debug level with its method, URL, status code, latency and attempt number. Requests that fail with a network error
+// are logged at the Warn level.
+func WithLogger(logger *slog.Logger) ClientOption {
+	return func(config *clientConfig) error {
+		if logger == nil {
+			return errors.New("The 'logger' must not be nil")
+		}
+		config.logger = logger
+		return nil
+	}
+}
+
+// WithBodyLogging adds the request headers and the request and response bodies to the entries logged by WithLogger().
+// The Authorization header and secret fields such as password, secretKey, smtpPassword, pemFileContent and adminUserInfo
+// are replaced with [REDACTED].
+func WithBodyLogging() ClientOption {
+	return func(config *clientConfig) error {
+		config.logBodies = true
+		return nil
+	}
+}
+
+// logRequest writes the request and its response, or the error returned instead, to the logger of the Client.
+func (c *Credentials) logRequest(ctx context.Context, request *http.Request, requestBody []byte, response *http.Response, responseBody []byte, attempt int, latency time.Duration, err error) {
+
+	if c.config == nil || c.config.logger == nil {
+		return
+	}
+
+	attrs := []slog.Attr{
+		slog.String("method", request.Method),
+		slog.String("url", request.URL.String()),
+		slog.Int("attempt", attempt),
+		slog.Duration("latency", latency),
+	}
+
+	level := slog.LevelDebug
+	if response != nil {
+		attrs = append(attrs, slog.Int("status", response.StatusCode))
+	}
+	if err != nil {
+		level = slog.LevelWarn
+		attrs = append(attrs, slog.String("error", err.Error()))
+	}
+
+	if c.config.logBodies {
+		attrs = append(attrs, slog.Any("requestHeaders", redactHeaders(request.Header)))
+		if len(requestBody) > 0 {
+			attrs = append(attrs, slog.String("requestBody", redactBody(requestBody)))
+		}
+		if len(responseBody) > 0 {
+			attrs = append(attrs, slog.String("responseBody", redactBody(responseBody)))
+		}
+	}
+
+	c.config.logger.LogAttrs(ctx, level, "Rubrik API request", attrs...)
+}
+
+// redactHeaders returns the HTTP headers with the value of every secret header replaced.
+func redactHeaders(header http.Header) map[string]string {
+
+	headers := map[string]string{}
+	for name, values := range header {
+		if redactedHeaders[http.CanonicalHeaderKey(name)] {
+			headers[name] = redacted
+		} else {
+			headers[name] = strings.Join(values, ", ")
+		}
+	}
+
+	return headers
+}
+
+// redactBody returns the JSON body with the value of every secret field replaced, truncated to maxLoggedBodySize.
+func redactBody(body []byte) string {
+
+	body = scrubBody(body)
+
+	if len(body) > maxLoggedBodySize {
+		return string(body[:maxLoggedBodySize]) + "...(truncated)"
+	}
+
+	return string(body)
+}
+
+// scrubBody returns the JSON body with the value of every secret field replaced and its fields in a consistent order. A
+// body that is not JSON is returned as-is.
+func scrubBody(body []byte) []byte {
+
+	var decodedBody interface{}
+	if err := json.Unmarshal(body, &decodedBody); err == nil {
+		if scrubbedBody, err := json.Marshal(redactValue(decodedBody)); err == nil {
+			return scrubbedBody
+		}
+	}
+
+	return body
+}
+
+// redactValue replaces the value of every secret field found in the decoded JSON value.
+func redactValue(value interface{}) interface{} {
+
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, fieldValue := range v {
+			if redactedFields[strings.ToLower(key)] {
+				v[key] = redacted
+			} else {
+				v[key] = redactValue(fieldValue)
+			}
+		}
+	case []interface{}:
+		for i, item := range v {
+			v[i] = redactValue(item)
+		}
+	}
+
+	return value
+}
@@ -0,0 +1,123 @@
+// Copyright 2018 Rubrik, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License prop
+//  http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rubrikcdm
+
+import (
+	"bytes"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestRedactHeaders(t *testing.T) {
+
+	header := http.Header{}
+	header.Set("Authorization", "Bearer secret-token")
+	header.Set("Cookie", "session=secret-cookie")
+	header.Set("Set-Cookie", "session=secret-cookie")
+	header.Set("Content-Type", "application/json")
+	// Non-canonical header names are still redacted
+	header["authorization"] = []string{"Basic c2VjcmV0"}
+
+	headers := redactHeaders(header)
+
+	for _, name := range []string{"Authorization", "Cookie", "Set-Cookie", "authorization"} {
+		if headers[name] != redacted {
+			t.Errorf("redactHeaders() %s = %q, want %q", name, headers[name], redacted)
+		}
+	}
+	if headers["Content-Type"] != "application/json" {
+		t.Errorf("redactHeaders() Content-Type = %q, want application/json", headers["Content-Type"])
+	}
+}
+
+func TestRedactBody(t *testing.T) {
+
+	tests := []struct {
+		name string
+		body string
+		want string
+	}{
+		{
+			"top level fields",
+			`{"username":"admin","password":"secret","token":"secret"}`,
+			`{"password":"[REDACTED]","token":"[REDACTED]","username":"admin"}`,
+		},
+		{
+			"case insensitive",
+			`{"Password":"secret","secretKey":"secret","SMTPPassword":"secret"}`,
+			`{"Password":"[REDACTED]","SMTPPassword":"[REDACTED]","secretKey":"[REDACTED]"}`,
+		},
+		{
+			"nested objects and arrays",
+			`{"adminUserInfo":{"password":"secret"},"data":[{"pemFileContent":"secret","name":"vcenter"}]}`,
+			`{"adminUserInfo":"[REDACTED]","data":[{"name":"vcenter","pemFileContent":"[REDACTED]"}]}`,
+		},
+		{
+			"not JSON",
+			`password=secret`,
+			`password=secret`,
+		},
+	}
+
+	for _, test := range tests {
+		if got := redactBody([]byte(test.body)); got != test.want {
+			t.Errorf("%s: redactBody() = %s, want %s", test.name, got, test.want)
+		}
+	}
+
+	large := `"` + strings.Repeat("a", maxLoggedBodySize) + `"`
+	if got := redactBody([]byte(large)); len(got) != maxLoggedBodySize+len("...(truncated)") {
+		t.Errorf("redactBody() of a large body returned %d bytes", len(got))
+	}
+}
+
+func TestLoggerDoesNotLogSecrets(t *testing.T) {
+
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v1/session":
+			w.Write([]byte(`{"id":"session01","token":"session-secret"}`))
+		default:
+			w.Write([]byte(`{"status":"ok"}`))
+		}
+	}))
+	defer server.Close()
+
+	var logs bytes.Buffer
+	logger := slog.New(slog.NewJSONHandler(&logs, &slog.HandlerOptions{Level: slog.LevelDebug}))
+
+	credentials := Connect(strings.TrimPrefix(server.URL, "https://"), "admin", "password-secret")
+	client, err := NewClient(credentials, WithHTTPClient(server.Client()), WithLogger(logger), WithBodyLogging())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	config := map[string]interface{}{"hostname": "smtp.example.com", "password": "smtp-secret"}
+	if _, err := client.Post("internal", "/smtp_instance", config); err != nil {
+		t.Fatal(err)
+	}
+
+	output := logs.String()
+	for _, secret := range []string{"password-secret", "session-secret", "smtp-secret", "Basic ", "Bearer "} {
+		if strings.Contains(output, secret) {
+			t.Errorf("the log contains %q:\n%s", secret, output)
+		}
+	}
+	if strings.Count(output, "Rubrik API request") != 2 || strings.Contains(output, redacted) == false {
+		t.Errorf("the log does not contain the redacted session and API requests:\n%s", output)
+	}
+}
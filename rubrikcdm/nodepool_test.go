@@ -0,0 +1,145 @@
+// Copyright 2018 Rubrik, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License prop
+//  http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rubrikcdm
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestNodePoolSelectNode(t *testing.T) {
+
+	pool := &nodePool{}
+	pool.add("10.0.0.1")
+	pool.add("10.0.0.2")
+	pool.seed("10.0.0.3")
+
+	var got []string
+	for i := 0; i < 4; i++ {
+		address, _ := pool.selectNode()
+		got = append(got, address)
+	}
+	if want := "10.0.0.3 10.0.0.2 10.0.0.1 10.0.0.3"; strings.Join(got, " ") != want {
+		t.Errorf("selectNode() = %s, want %s", strings.Join(got, " "), want)
+	}
+
+	// An unhealthy node is skipped and only probed once the unhealthy period has elapsed
+	pool.markUnhealthy("10.0.0.2")
+	for i := 0; i < 4; i++ {
+		if address, probes := pool.selectNode(); address == "10.0.0.2" || len(probes) != 0 {
+			t.Fatalf("selectNode() = %s, %v with 10.0.0.2 unhealthy", address, probes)
+		}
+	}
+	pool.nodes[1].unhealthyUntil = time.Now().Add(-time.Second)
+	if _, probes := pool.selectNode(); len(probes) != 1 || probes[0] != "10.0.0.2" {
+		t.Errorf("selectNode() probes = %v, want [10.0.0.2]", probes)
+	}
+	if _, probes := pool.selectNode(); len(probes) != 0 {
+		t.Errorf("selectNode() probes = %v while the probe is in progress", probes)
+	}
+
+	// The node that recovers first is returned when every node is unhealthy
+	pool.markUnhealthy("10.0.0.2")
+	pool.markUnhealthy("10.0.0.1")
+	pool.markUnhealthy("10.0.0.3")
+	pool.nodes[2].unhealthyUntil = time.Now().Add(time.Second)
+	if address, _ := pool.selectNode(); address != "10.0.0.1" {
+		t.Errorf("selectNode() with every node unhealthy = %s, want 10.0.0.1", address)
+	}
+}
+
+func TestClientProbesUnhealthyNode(t *testing.T) {
+
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"version":"9.0.1"}`))
+	}
+	live := httptest.NewTLSServer(http.HandlerFunc(handler))
+	defer live.Close()
+
+	// The recovering node drops every connection while down is set
+	var down, requests int32 = 1, 0
+	recovering := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.LoadInt32(&down) == 1 {
+			conn, _, _ := w.(http.Hijacker).Hijack()
+			conn.Close()
+			return
+		}
+		atomic.AddInt32(&requests, 1)
+		handler(w, r)
+	}))
+	defer recovering.Close()
+
+	recoveringNode := strings.TrimPrefix(recovering.URL, "https://")
+	client, err := NewClient(ConnectAPIToken(strings.TrimPrefix(live.URL, "https://"), "token"),
+		WithHTTPClient(live.Client()), WithNodes(recoveringNode))
+	if err != nil {
+		t.Fatal(err)
+	}
+	pool := client.config.nodes
+
+	// probe expires the unhealthy period of the node, sends a request to trigger the probe and waits for its result
+	probe := func() {
+		pool.mu.Lock()
+		pool.nodes[1].unhealthyUntil = time.Now().Add(-time.Second)
+		pool.mu.Unlock()
+
+		if _, err := client.ClusterVersion(); err != nil {
+			t.Fatal(err)
+		}
+		for deadline := time.Now().Add(5 * time.Second); time.Now().Before(deadline); time.Sleep(10 * time.Millisecond) {
+			pool.mu.Lock()
+			probing := pool.nodes[1].probing
+			pool.mu.Unlock()
+			if probing == false {
+				return
+			}
+		}
+		t.Fatal("the probe did not complete")
+	}
+
+	for i := 0; i < 4; i++ {
+		if _, err := client.ClusterVersion(); err != nil {
+			t.Fatalf("ClusterVersion() returned an error with one node down: %v", err)
+		}
+	}
+	if pool.nodes[1].unhealthy == false {
+		t.Fatal("the node that is down was not marked unhealthy")
+	}
+
+	// A failed probe keeps the node unhealthy for another period
+	probe()
+	if pool.nodes[1].unhealthy == false || pool.nodes[1].unhealthyUntil.Before(time.Now()) {
+		t.Fatal("the node that is down was marked healthy by the probe")
+	}
+
+	atomic.StoreInt32(&down, 0)
+	probe()
+	if pool.nodes[1].unhealthy {
+		t.Fatal("the node that recovered is still unhealthy after the probe")
+	}
+
+	atomic.StoreInt32(&requests, 0)
+	for i := 0; i < 4; i++ {
+		if _, err := client.ClusterVersion(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if requests != 2 {
+		t.Errorf("the node that recovered received %d of 4 requests, want 2", requests)
+	}
+}
@@ -0,0 +1,140 @@
+// Copyright 2018 Rubrik, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License prop
+//  http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rubrikcdm
+
+import (
+	"errors"
+	"strings"
+	"sync"
+	"time"
+)
+
+// objectCacheEntry is an object ID and the time it expires from the cache.
+type objectCacheEntry struct {
+	id      string
+	expires time.Time
+}
+
+// objectCache maps the objects found through ObjectID() and FindObject() to their ID.
+type objectCache struct {
+	ttl time.Duration
+
+	mu      sync.Mutex
+	entries map[ObjectQuery]objectCacheEntry
+}
+
+// WithObjectCache caches the IDs returned by ObjectID() and FindObject() for the provided "ttl" so that the helper
+// functions (ex. AssignSLA() or OnDemandSnapshotVM()) do not look up the same object and SLA Domain names on every
+// call. An ID is removed from the cache as soon as the Rubrik cluster responds to an API call on it with a 404 Not
+// Found (ex. the VM was deleted and added again). Use InvalidateObjectID(), InvalidateObject() or
+// InvalidateObjectCache() when objects are renamed or replaced outside of the Client.
+func WithObjectCache(ttl time.Duration) ClientOption {
+	return func(config *clientConfig) error {
+		if ttl <= 0 {
+			return errors.New("The object cache 'ttl' must be greater than 0")
+		}
+		config.objectCache = &objectCache{ttl: ttl, entries: map[ObjectQuery]objectCacheEntry{}}
+		return nil
+	}
+}
+
+// InvalidateObjectID removes the ID of the object from the cache enabled by WithObjectCache() so that the next call to
+// ObjectID() requests it from the Rubrik cluster. The arguments are the same as the ones provided to ObjectID().
+func (c *Credentials) InvalidateObjectID(objectName string, objectType ObjectType, hostOS ...string) {
+	query := ObjectQuery{Type: objectType, Name: objectName}
+	if parsed, err := ParseObjectType(string(objectType)); err == nil {
+		query.Type = parsed
+	}
+	if query.Type == ObjectTypeFilesetTemplate && len(hostOS) > 0 {
+		query.HostOS = hostOS[0]
+	}
+	c.InvalidateObject(query)
+}
+
+// InvalidateObject removes the ID of the object found through FindObject() from the cache enabled by WithObjectCache().
+func (c *Credentials) InvalidateObject(query ObjectQuery) {
+	if parsed, err := ParseObjectType(string(query.Type)); err == nil {
+		query.Type = parsed
+	}
+	if cache := c.objectCache(); cache != nil {
+		cache.delete(query)
+	}
+}
+
+// InvalidateObjectCache removes every ID from the cache enabled by WithObjectCache().
+func (c *Credentials) InvalidateObjectCache() {
+	if cache := c.objectCache(); cache != nil {
+		cache.mu.Lock()
+		cache.entries = map[ObjectQuery]objectCacheEntry{}
+		cache.mu.Unlock()
+	}
+}
+
+// objectCache returns the object cache of the Client or nil when it is not enabled.
+func (c *Credentials) objectCache() *objectCache {
+	if c.config == nil {
+		return nil
+	}
+	return c.config.objectCache
+}
+
+// get returns the ID cached for the query when it has not expired.
+func (cache *objectCache) get(query ObjectQuery) (string, bool) {
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+
+	entry, ok := cache.entries[query]
+	if ok == false {
+		return "", false
+	}
+	if time.Now().After(entry.expires) {
+		delete(cache.entries, query)
+		return "", false
+	}
+	return entry.id, true
+}
+
+// set caches the ID of the query for the TTL of the cache.
+func (cache *objectCache) set(query ObjectQuery, id string) {
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+
+	cache.entries[query] = objectCacheEntry{id: id, expires: time.Now().Add(cache.ttl)}
+}
+
+// delete removes the query from the cache.
+func (cache *objectCache) delete(query ObjectQuery) {
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+
+	delete(cache.entries, query)
+}
+
+// evictPath removes every cached ID that is one of the segments of the path of an API call the Rubrik cluster
+// responded to with a 404 Not Found (ex. /api/v1/vmware/vm/{id}).
+func (cache *objectCache) evictPath(path string) {
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+
+	segments := map[string]bool{}
+	for _, segment := range strings.Split(path, "/") {
+		segments[segment] = true
+	}
+
+	for query, entry := range cache.entries {
+		if segments[entry.id] {
+			delete(cache.entries, query)
+		}
+	}
+}
@@ -0,0 +1,184 @@
+// Copyright 2018 Rubrik, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License prop
+//  http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rubrikcdm
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ObjectType is the type of a Rubrik object passed as the "objectType" of ObjectID() and the helper functions that act
+// on an object (ex. AssignSLA()). String literals such as "vmware" can still be used in place of the constants.
+type ObjectType string
+
+const (
+	// ObjectTypeVMware is a vSphere VM.
+	ObjectTypeVMware ObjectType = "vmware"
+	// ObjectTypeSLA is an SLA Domain.
+	ObjectTypeSLA ObjectType = "sla"
+	// ObjectTypeVMwareHost is an ESXi host.
+	ObjectTypeVMwareHost ObjectType = "vmwareHost"
+	// ObjectTypeVMwareDatastore is a vSphere datastore.
+	ObjectTypeVMwareDatastore ObjectType = "vmwareDatastore"
+	// ObjectTypePhysicalHost is a Linux or Windows host, the name is its hostname.
+	ObjectTypePhysicalHost ObjectType = "physicalHost"
+	// ObjectTypeFilesetTemplate is a Fileset Template, it requires the host OS.
+	ObjectTypeFilesetTemplate ObjectType = "filesetTemplate"
+	// ObjectTypeFileset is a fileset of a physical host, the name is the name of its Fileset Template.
+	ObjectTypeFileset ObjectType = "fileset"
+	// ObjectTypeManagedVolume is a Managed Volume.
+	ObjectTypeManagedVolume ObjectType = "managedVolume"
+	// ObjectTypeVCenter is a vCenter Server.
+	ObjectTypeVCenter ObjectType = "vcenter"
+	// ObjectTypeEC2 is an AWS EC2 instance, the name is its Instance ID.
+	ObjectTypeEC2 ObjectType = "ec2"
+	// ObjectTypeAHV is a Nutanix AHV VM.
+	ObjectTypeAHV ObjectType = "ahv"
+	// ObjectTypeHyperV is a Hyper-V VM.
+	ObjectTypeHyperV ObjectType = "hyperv"
+	// ObjectTypeMSSQLDB is a SQL Server database.
+	ObjectTypeMSSQLDB ObjectType = "mssqlDB"
+	// ObjectTypeMSSQLInstance is a SQL Server instance.
+	ObjectTypeMSSQLInstance ObjectType = "mssqlInstance"
+	// ObjectTypeOracleDB is an Oracle database.
+	ObjectTypeOracleDB ObjectType = "oracleDB"
+	// ObjectTypeNASShare is an NFS or SMB share of a NAS host, the name is its export point.
+	ObjectTypeNASShare ObjectType = "nasShare"
+	// ObjectTypeVolumeGroup is a Windows volume group.
+	ObjectTypeVolumeGroup ObjectType = "volumeGroup"
+	// ObjectTypeOrganization is a multi-tenancy organization.
+	ObjectTypeOrganization ObjectType = "organization"
+	// ObjectTypeReplicationTarget is a replication target, the name is the name of the target Rubrik cluster.
+	ObjectTypeReplicationTarget ObjectType = "replicationTarget"
+)
+
+// objectTypes are the ObjectType constants in the order they are listed in error messages.
+var objectTypes = []ObjectType{
+	ObjectTypeVMware,
+	ObjectTypeSLA,
+	ObjectTypeVMwareHost,
+	ObjectTypeVMwareDatastore,
+	ObjectTypePhysicalHost,
+	ObjectTypeFilesetTemplate,
+	ObjectTypeFileset,
+	ObjectTypeManagedVolume,
+	ObjectTypeVCenter,
+	ObjectTypeEC2,
+	ObjectTypeAHV,
+	ObjectTypeHyperV,
+	ObjectTypeMSSQLDB,
+	ObjectTypeMSSQLInstance,
+	ObjectTypeOracleDB,
+	ObjectTypeNASShare,
+	ObjectTypeVolumeGroup,
+	ObjectTypeOrganization,
+	ObjectTypeReplicationTarget,
+}
+
+// supportedObjectTypes is the ObjectType accepted by each helper function that takes an "objectType".
+var supportedObjectTypes = map[string][]ObjectType{
+	"ObjectID": objectTypes,
+	"AssignSLA": {
+		ObjectTypeVMware,
+		ObjectTypeAHV,
+		ObjectTypeHyperV,
+		ObjectTypeFileset,
+		ObjectTypeMSSQLDB,
+		ObjectTypeMSSQLInstance,
+		ObjectTypeOracleDB,
+		ObjectTypeManagedVolume,
+		ObjectTypeNASShare,
+		ObjectTypeVolumeGroup,
+		ObjectTypeEC2,
+	},
+	"Snapshots": {
+		ObjectTypeVMware,
+		ObjectTypeAHV,
+		ObjectTypeHyperV,
+		ObjectTypeFileset,
+		ObjectTypeManagedVolume,
+		ObjectTypeMSSQLDB,
+		ObjectTypeOracleDB,
+		ObjectTypeEC2,
+		ObjectTypeVolumeGroup,
+	},
+	"RestoreFiles":         {ObjectTypeFileset, ObjectTypeVMware},
+	"GetSLAObjects":        {ObjectTypeVMware},
+	"PauseSnapshot":        {ObjectTypeVMware},
+	"ResumeSnapshot":       {ObjectTypeVMware},
+	"OnDemandSnapshotVM":   {ObjectTypeVMware},
+	"EndUserAuthorization": {ObjectTypeVMware},
+}
+
+// ObjectTypes returns every ObjectType.
+func ObjectTypes() []ObjectType {
+	return append([]ObjectType{}, objectTypes...)
+}
+
+// ParseObjectType returns the ObjectType that matches the provided string regardless of its case (ex. "VMware" returns
+// ObjectTypeVMware).
+func ParseObjectType(objectType string) (ObjectType, error) {
+	for _, validType := range objectTypes {
+		if strings.EqualFold(string(validType), objectType) {
+			return validType, nil
+		}
+	}
+	return "", fmt.Errorf("'%s' is not a valid ObjectType", objectType)
+}
+
+// SupportedObjectTypes returns the ObjectType accepted by the provided helper function (ex. "AssignSLA"), or nil when
+// the function does not take an "objectType".
+func SupportedObjectTypes(function string) []ObjectType {
+	return append([]ObjectType(nil), supportedObjectTypes[function]...)
+}
+
+// String returns the ObjectType as it is passed to ObjectID() (ex. vmware).
+func (t ObjectType) String() string {
+	return string(t)
+}
+
+// checkObjectType returns the ObjectType matching "objectType" when it is supported by the helper function, or an error
+// listing the supported types.
+func checkObjectType(function string, objectType ObjectType) (ObjectType, error) {
+
+	supported := supportedObjectTypes[function]
+
+	if parsed, err := ParseObjectType(string(objectType)); err == nil {
+		for _, validType := range supported {
+			if parsed == validType {
+				return parsed, nil
+			}
+		}
+	}
+
+	choices := make([]string, len(supported))
+	for i, validType := range supported {
+		choices[i] = string(validType)
+	}
+	return "", fmt.Errorf("The 'objectType' must be %s", quotedChoices(choices))
+}
+
+// quotedChoices returns the values formatted for an error message, ex. 'Linux' or 'Windows'.
+func quotedChoices(values []string) string {
+
+	quoted := make([]string, len(values))
+	for i, value := range values {
+		quoted[i] = fmt.Sprintf("'%s'", value)
+	}
+
+	if len(quoted) < 3 {
+		return strings.Join(quoted, " or ")
+	}
+	return strings.Join(quoted[:len(quoted)-1], ", ") + ", or " + quoted[len(quoted)-1]
+}
@@ -0,0 +1,314 @@
+// Copyright 2018 Rubrik, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License prop
+//  http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rubrikcdm
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Client is a Rubrik API client built around Credentials. Every Credentials function is available on a Client, but the
+// HTTP connection to the Rubrik cluster is configured through the ClientOption values provided to NewClient() instead of
+// the insecure, single-use connection created by Connect(), ConnectAPIToken() and ConnectEnv().
+type Client struct {
+	*Credentials
+}
+
+// ClientOption configures the HTTP connection used by a Client.
+type ClientOption func(*clientConfig) error
+
+// clientConfig holds the connection settings shared by every request sent through a Client.
+type clientConfig struct {
+	httpClient *http.Client
+	transport  http.RoundTripper
+
+	rootCAs            *x509.CertPool
+	pinnedFingerprints [][]byte
+	clientCertificates []tls.Certificate
+	proxy              *url.URL
+	insecureSkipVerify bool
+
+	// basicAuth sends the username and password with every request instead of using a session token
+	basicAuth    bool
+	sessionMu    sync.Mutex
+	sessionToken string
+
+	retryPolicy *RetryPolicy
+	pollPolicy  *PollPolicy
+
+	nodes *nodePool
+
+	// version caches the CDM version of the Rubrik cluster
+	versionMu sync.Mutex
+	version   *CDMVersion
+
+	objectCache *objectCache
+
+	logger    *slog.Logger
+	logBodies bool
+
+	tracerProvider trace.TracerProvider
+	meterProvider  metric.MeterProvider
+	telemetry      *telemetry
+
+	cassette *cassette
+}
+
+// NewClient creates a Client from the provided Credentials (ex. the value returned by ConnectEnv()). Unlike Credentials
+// created through Connect(), a Client verifies the certificate presented by the Rubrik cluster against the system
+// certificate pool unless WithCACertificates(), WithPinnedCertificate() or WithInsecureSkipVerify() are provided, and
+// reuses connections between requests.
+//
+// When the Credentials contain a username and password, the Client exchanges them for a session token through
+// POST /v1/session on the first request and creates a new session whenever the token expires. Call Close() once the
+// Client is no longer needed to delete the session.
+//
+// API calls that fail because of a transient condition are retried according to DefaultRetryPolicy() unless
+// WithRetryPolicy() is provided.
+//
+// WithNodes() and WithNodeDiscovery() distribute requests across the nodes of the Rubrik cluster so that API calls keep
+// working while the node provided to Connect() is down for maintenance.
+//
+// Every API call is traced and measured through the global OpenTelemetry providers unless WithTracerProvider() or
+// WithMeterProvider() are provided.
+func NewClient(credentials *Credentials, options ...ClientOption) (*Client, error) {
+
+	if credentials == nil {
+		return nil, errors.New("The 'credentials' must not be nil")
+	}
+
+	config := &clientConfig{}
+	for _, option := range options {
+		if err := option(config); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := config.build(); err != nil {
+		return nil, err
+	}
+
+	// Copy the credentials so the caller's value keeps its original behavior
+	client := *credentials
+	client.config = config
+	client.timezone = &timezoneCache{}
+
+	if config.nodes != nil {
+		config.nodes.seed(client.NodeIP)
+	}
+
+	return &Client{Credentials: &client}, nil
+}
+
+// WithHTTPClient uses the provided http.Client for every request sent to the Rubrik cluster. The client is used as-is and
+// can not be combined with the TLS or proxy options.
+func WithHTTPClient(httpClient *http.Client) ClientOption {
+	return func(config *clientConfig) error {
+		if httpClient == nil {
+			return errors.New("The 'httpClient' must not be nil")
+		}
+		config.httpClient = httpClient
+		return nil
+	}
+}
+
+// WithTransport uses the provided http.RoundTripper for every request sent to the Rubrik cluster. The RoundTripper is
+// used as-is and can not be combined with the TLS or proxy options.
+func WithTransport(transport http.RoundTripper) ClientOption {
+	return func(config *clientConfig) error {
+		if transport == nil {
+			return errors.New("The 'transport' must not be nil")
+		}
+		config.transport = transport
+		return nil
+	}
+}
+
+// WithCACertificates verifies the certificate presented by the Rubrik cluster against the PEM encoded CA certificates
+// in "caPEM" instead of the system certificate pool.
+func WithCACertificates(caPEM []byte) ClientOption {
+	return func(config *clientConfig) error {
+		if config.rootCAs == nil {
+			config.rootCAs = x509.NewCertPool()
+		}
+		if config.rootCAs.AppendCertsFromPEM(caPEM) == false {
+			return errors.New("The 'caPEM' does not contain a valid PEM encoded certificate")
+		}
+		return nil
+	}
+}
+
+// WithPinnedCertificate only accepts a Rubrik cluster whose leaf certificate matches the provided SHA-256 fingerprint. The
+// fingerprint is a hex string and may contain ':' separators (ex. the output of `openssl x509 -fingerprint -sha256`).
+// The option may be provided multiple times to accept several certificates. When used without WithCACertificates(),
+// the pin replaces the certificate chain verification which allows the self-signed certificate of a Rubrik cluster to be used.
+func WithPinnedCertificate(sha256Fingerprint string) ClientOption {
+	return func(config *clientConfig) error {
+		fingerprint, err := hex.DecodeString(strings.ReplaceAll(sha256Fingerprint, ":", ""))
+		if err != nil || len(fingerprint) != sha256.Size {
+			return fmt.Errorf("'%s' is not a valid SHA-256 certificate fingerprint", sha256Fingerprint)
+		}
+		config.pinnedFingerprints = append(config.pinnedFingerprints, fingerprint)
+		return nil
+	}
+}
+
+// WithClientCertificate presents the PEM encoded certificate and private key to the Rubrik cluster for mutual TLS authentication.
+func WithClientCertificate(certPEM, keyPEM []byte) ClientOption {
+	return func(config *clientConfig) error {
+		certificate, err := tls.X509KeyPair(certPEM, keyPEM)
+		if err != nil {
+			return err
+		}
+		config.clientCertificates = append(config.clientCertificates, certificate)
+		return nil
+	}
+}
+
+// WithProxy sends every request through the provided HTTP proxy (ex. http://proxy.example.com:3128). When not provided, the
+// proxy configured through the HTTPS_PROXY and NO_PROXY environment variables is used.
+func WithProxy(proxyURL string) ClientOption {
+	return func(config *clientConfig) error {
+		proxy, err := url.Parse(proxyURL)
+		if err != nil {
+			return err
+		}
+		config.proxy = proxy
+		return nil
+	}
+}
+
+// WithInsecureSkipVerify disables the verification of the certificate presented by the Rubrik cluster. This matches the
+// behavior of Connect() and should only be used in lab environments.
+func WithInsecureSkipVerify() ClientOption {
+	return func(config *clientConfig) error {
+		config.insecureSkipVerify = true
+		return nil
+	}
+}
+
+// WithBasicAuth sends the username and password with every request instead of creating a session on the Rubrik cluster.
+func WithBasicAuth() ClientOption {
+	return func(config *clientConfig) error {
+		config.basicAuth = true
+		return nil
+	}
+}
+
+// build validates the combination of options and creates the http.Client shared by every request.
+func (config *clientConfig) build() error {
+
+	tlsOptions := config.rootCAs != nil || len(config.pinnedFingerprints) > 0 || len(config.clientCertificates) > 0 || config.proxy != nil || config.insecureSkipVerify
+
+	if config.httpClient != nil && config.transport != nil {
+		return errors.New("WithHTTPClient and WithTransport can not be used together")
+	}
+
+	if (config.httpClient != nil || config.transport != nil) && tlsOptions {
+		return errors.New("The TLS and proxy options can not be combined with WithHTTPClient or WithTransport")
+	}
+
+	if config.insecureSkipVerify && (config.rootCAs != nil || len(config.pinnedFingerprints) > 0) {
+		return errors.New("WithInsecureSkipVerify can not be combined with WithCACertificates or WithPinnedCertificate")
+	}
+
+	if config.logBodies && config.logger == nil {
+		return errors.New("WithBodyLogging can only be used with WithLogger")
+	}
+
+	if config.tracerProvider != nil || config.meterProvider != nil {
+		tracerProvider, meterProvider := config.tracerProvider, config.meterProvider
+		if tracerProvider == nil {
+			tracerProvider = otel.GetTracerProvider()
+		}
+		if meterProvider == nil {
+			meterProvider = otel.GetMeterProvider()
+		}
+		config.telemetry = newTelemetry(tracerProvider, meterProvider)
+	}
+
+	if config.httpClient == nil {
+		transport := config.transport
+		if transport == nil {
+			httpTransport := http.DefaultTransport.(*http.Transport).Clone()
+			httpTransport.TLSClientConfig = config.tlsConfig()
+			if config.proxy != nil {
+				httpTransport.Proxy = http.ProxyURL(config.proxy)
+			}
+			transport = httpTransport
+		}
+
+		config.httpClient = &http.Client{Transport: transport}
+	}
+
+	if config.cassette != nil {
+		// Copy the client so an http.Client provided through WithHTTPClient is not modified
+		httpClient := *config.httpClient
+		transport := httpClient.Transport
+		if transport == nil {
+			transport = http.DefaultTransport
+		}
+		httpClient.Transport = &cassetteTransport{cassette: config.cassette, transport: transport}
+		config.httpClient = &httpClient
+	}
+
+	return nil
+}
+
+// tlsConfig returns the TLS configuration for the provided certificate options.
+func (config *clientConfig) tlsConfig() *tls.Config {
+
+	tlsConfig := &tls.Config{
+		MinVersion:         tls.VersionTLS12,
+		RootCAs:            config.rootCAs,
+		Certificates:       config.clientCertificates,
+		InsecureSkipVerify: config.insecureSkipVerify,
+	}
+
+	if len(config.pinnedFingerprints) > 0 {
+		// Without a CA bundle the pin is the only verification performed on the cluster certificate
+		if config.rootCAs == nil {
+			tlsConfig.InsecureSkipVerify = true
+		}
+		pins := config.pinnedFingerprints
+		tlsConfig.VerifyConnection = func(state tls.ConnectionState) error {
+			if len(state.PeerCertificates) == 0 {
+				return errors.New("The Rubrik cluster did not present a certificate")
+			}
+			fingerprint := sha256.Sum256(state.PeerCertificates[0].Raw)
+			for _, pin := range pins {
+				if bytes.Equal(pin, fingerprint[:]) {
+					return nil
+				}
+			}
+			return fmt.Errorf("The Rubrik cluster certificate fingerprint '%s' does not match any pinned certificate", hex.EncodeToString(fingerprint[:]))
+		}
+	}
+
+	return tlsConfig
+}
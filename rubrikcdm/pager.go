@@ -0,0 +1,237 @@
+// Copyright 2018 Rubrik, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License prop
+//  http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rubrikcdm
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// DefaultPageSize is the number of objects requested per page when a page size is not provided to NewPager().
+const DefaultPageSize = 500
+
+// pageResponse corresponds to the common fields of every paginated Rubrik API response.
+type pageResponse struct {
+	HasMore    bool              `json:"hasMore"`
+	Data       []json.RawMessage `json:"data"`
+	Total      int               `json:"total"`
+	NextCursor string            `json:"nextCursor"`
+}
+
+// Pager lazily iterates over every object returned by a paginated Rubrik API endpoint (ex. /v1/vmware/vm), requesting
+// the next page only once the objects of the current page have been consumed. Each object is decoded into a value of
+// type T using encoding/json, ex:
+//
+//	pager := rubrikcdm.NewPager[map[string]interface{}](rubrik, "v1", "/vmware/vm?is_relic=false", 0)
+//	for pager.Next() {
+//		fmt.Println(pager.Item()["name"])
+//	}
+//	if err := pager.Err(); err != nil {
+//		log.Fatal(err)
+//	}
+type Pager[T any] struct {
+	credentials     *Credentials
+	apiVersion      string
+	apiEndpoint     string
+	pageSize        int
+	cursorParameter string
+	timeout         int
+
+	page   []json.RawMessage
+	index  int
+	offset int
+	cursor string
+	done   bool
+	item   T
+	err    error
+}
+
+// NewPager returns a Pager that requests the pages of the API endpoint through the "limit" and "offset" query parameters.
+// A "pageSize" of 0 uses the DefaultPageSize.
+func NewPager[T any](c *Credentials, apiVersion, apiEndpoint string, pageSize int, timeout ...int) *Pager[T] {
+	if pageSize <= 0 {
+		pageSize = DefaultPageSize
+	}
+
+	return &Pager[T]{
+		credentials: c,
+		apiVersion:  apiVersion,
+		apiEndpoint: apiEndpoint,
+		pageSize:    pageSize,
+		timeout:     httpTimeout(timeout),
+	}
+}
+
+// NewCursorPager returns a Pager for API endpoints that use cursor based pagination. The "nextCursor" field of each API
+// response is sent through the "cursorParameter" query parameter (ex. cursor) to request the next page.
+func NewCursorPager[T any](c *Credentials, apiVersion, apiEndpoint, cursorParameter string, pageSize int, timeout ...int) *Pager[T] {
+	pager := NewPager[T](c, apiVersion, apiEndpoint, pageSize, timeout...)
+	pager.cursorParameter = cursorParameter
+
+	return pager
+}
+
+// Next advances the Pager to the next object, requesting the next page from the Rubrik cluster when required. It returns
+// false once every object has been returned or an error occurred.
+func (p *Pager[T]) Next() bool {
+	return p.NextContext(context.Background())
+}
+
+// NextContext is the same as Next with the addition of a context.Context that is used to cancel in-flight requests.
+func (p *Pager[T]) NextContext(ctx context.Context) bool {
+
+	if p.err != nil {
+		return false
+	}
+
+	for p.index >= len(p.page) {
+		if p.done {
+			return false
+		}
+		if err := p.fetch(ctx); err != nil {
+			p.err = err
+			return false
+		}
+	}
+
+	var item T
+	if err := json.Unmarshal(p.page[p.index], &item); err != nil {
+		p.err = fmt.Errorf("Unable to decode the %s%s API response into %T: %w", p.apiVersion, p.apiEndpoint, item, err)
+		return false
+	}
+	p.item = item
+	p.index++
+
+	return true
+}
+
+// Item returns the current object.
+func (p *Pager[T]) Item() T {
+	return p.item
+}
+
+// Err returns the error, if any, that stopped the Pager.
+func (p *Pager[T]) Err() error {
+	return p.err
+}
+
+// fetch requests the next page of the API endpoint.
+func (p *Pager[T]) fetch(ctx context.Context) error {
+
+	query := url.Values{}
+	query.Set("limit", fmt.Sprint(p.pageSize))
+	if p.cursorParameter == "" {
+		query.Set("offset", fmt.Sprint(p.offset))
+	} else if p.cursor != "" {
+		query.Set(p.cursorParameter, p.cursor)
+	}
+
+	separator := "?"
+	if strings.Contains(p.apiEndpoint, "?") {
+		separator = "&"
+	}
+
+	// Escape the endpoint in the same way as Get() while keeping the encoded cursor intact
+	apiEndpoint := getEscape(p.apiEndpoint) + separator + query.Encode()
+
+	page, err := decodeAPI[pageResponse](ctx, p.credentials, "GET_ENCODED", p.apiVersion, apiEndpoint, nil, p.timeout)
+	if err != nil {
+		return err
+	}
+
+	p.page = page.Data
+	p.index = 0
+	p.offset += len(page.Data)
+
+	if p.cursorParameter != "" {
+		if page.NextCursor == "" || page.NextCursor == p.cursor {
+			p.done = true
+		}
+		p.cursor = page.NextCursor
+	} else {
+		// Some endpoints only report the total number of objects instead of hasMore
+		more := page.HasMore || p.offset < page.Total
+		if more == false || len(page.Data) == 0 {
+			p.done = true
+		}
+	}
+
+	return nil
+}
+
+// ListAll returns every object of a paginated Rubrik API endpoint decoded into a value of type T.
+func ListAll[T any](c *Credentials, apiVersion, apiEndpoint string, timeout ...int) ([]T, error) {
+	return ListAllContext[T](context.Background(), c, apiVersion, apiEndpoint, timeout...)
+}
+
+// ListAllContext is the same as ListAll with the addition of a context.Context that is used to cancel in-flight requests.
+func ListAllContext[T any](ctx context.Context, c *Credentials, apiVersion, apiEndpoint string, timeout ...int) ([]T, error) {
+
+	items := []T{}
+	err := ForEachContext(ctx, c, apiVersion, apiEndpoint, func(item T) error {
+		items = append(items, item)
+		return nil
+	}, timeout...)
+	if err != nil {
+		return nil, err
+	}
+
+	return items, nil
+}
+
+// ErrStopIteration can be returned by the function provided to ForEach to stop the iteration without returning an error.
+var ErrStopIteration = errors.New("Stop iteration")
+
+// ForEach calls "fn" for every object of a paginated Rubrik API endpoint, requesting each page only once the previous
+// page has been processed. The iteration stops at the first error returned by "fn", which is returned by ForEach
+// unless it is ErrStopIteration.
+func ForEach[T any](c *Credentials, apiVersion, apiEndpoint string, fn func(item T) error, timeout ...int) error {
+	return ForEachContext(context.Background(), c, apiVersion, apiEndpoint, fn, timeout...)
+}
+
+// ForEachContext is the same as ForEach with the addition of a context.Context that is used to cancel in-flight requests.
+func ForEachContext[T any](ctx context.Context, c *Credentials, apiVersion, apiEndpoint string, fn func(item T) error, timeout ...int) error {
+
+	pager := NewPager[T](c, apiVersion, apiEndpoint, 0, timeout...)
+	for pager.NextContext(ctx) {
+		if err := fn(pager.Item()); err != nil {
+			if errors.Is(err, ErrStopIteration) {
+				return nil
+			}
+			return err
+		}
+	}
+
+	return pager.Err()
+}
+
+// getAllContext returns every page of a paginated Rubrik API endpoint merged into a single API response with the same
+// structure as GetContext, ex. {"hasMore": false, "data": [...], "total": 1234}.
+func (c *Credentials) getAllContext(ctx context.Context, apiVersion, apiEndpoint string, timeout int) (interface{}, error) {
+
+	items, err := ListAllContext[interface{}](ctx, c, apiVersion, apiEndpoint, timeout)
+	if err != nil {
+		return nil, err
+	}
+
+	return map[string]interface{}{
+		"hasMore": false,
+		"data":    items,
+		"total":   float64(len(items)),
+	}, nil
+}
@@ -0,0 +1,418 @@
+// Copyright 2018 Rubrik, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License prop
+//  http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rubrikcdm
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// VMRecoveryOptions are the target and settings of the VM created by LiveMountVM(), InstantRecoverVM() and ExportVM().
+// The network adapters of the new VM are connected to the networks of the source VM unless NetworkBindings,
+// DisableNetwork or RemoveNetworkDevices is set.
+type VMRecoveryOptions struct {
+	// VMName is the name of the new VM. The Rubrik cluster generates a name when it is empty
+	VMName string
+	// Host is the name of the ESXi host of the new VM. It defaults to the host of the source VM and is required by ExportVM()
+	Host string
+	// Datastore is the name of the datastore created for a Live Mount or, for ExportVM(), the name of the existing
+	// datastore the VM is exported to. It can not be used with InstantRecoverVM()
+	Datastore string
+	// DisableNetwork connects the network adapters of the new VM to no network. It can not be used with InstantRecoverVM()
+	DisableNetwork bool
+	// RemoveNetworkDevices removes the network adapters from the new VM
+	RemoveNetworkDevices bool
+	// NetworkBindings connects network adapters of the new VM to other networks than the networks of the source VM. It
+	// requires CDM 5.3 or later and can not be used with DisableNetwork or RemoveNetworkDevices
+	NetworkBindings []VMNetworkBinding
+	// KeepMACAddresses keeps the MAC addresses of the network adapters of the source VM
+	KeepMACAddresses bool
+	// PowerOn powers on the new VM once it has been created
+	PowerOn bool
+	// PreserveMOID keeps the vSphere managed object ID of the source VM. It can only be used with InstantRecoverVM()
+	PreserveMOID bool
+}
+
+// VMNetworkBinding connects a network adapter of the VM created by LiveMountVM(), InstantRecoverVM() or ExportVM() to a
+// vSphere network.
+type VMNetworkBinding struct {
+	// NetworkAdapter is the label of the network adapter of the source VM (ex. Network adapter 1)
+	NetworkAdapter string
+	// Network is the name of the port group or distributed port group the network adapter is connected to
+	Network string
+}
+
+// VMRecovery is the Job monitoring a LiveMountVM(), InstantRecoverVM() or ExportVM() request along with the snapshot
+// it recovers.
+type VMRecovery struct {
+	*Job
+	// SnapshotID is the ID of the snapshot chosen by the SnapshotSelector
+	SnapshotID string
+}
+
+// MountID returns the ID of the Live Mount created by the job, to provide to UnmountVM(), once the job has succeeded. It
+// returns an empty string until Wait() or Poll() report that the job has succeeded.
+func (r *VMRecovery) MountID() string {
+	for _, link := range r.Status().Links {
+		if link.Rel != "result" {
+			continue
+		}
+		if index := strings.Index(link.Href, "/vmware/vm/snapshot/mount/"); index != -1 {
+			return strings.Trim(link.Href[index+len("/vmware/vm/snapshot/mount/"):], "/")
+		}
+	}
+	return ""
+}
+
+// LiveMountVM mounts the snapshot of the vSphere VM chosen by the selector (ex. Latest()) as a new VM running from the
+// Rubrik cluster. Use Wait() to wait for the Live Mount to be ready, MountID() to get its ID and UnmountVM() to remove
+// it.
+func (c *Credentials) LiveMountVM(vmName string, selector SnapshotSelector, options VMRecoveryOptions, timeout ...int) (*VMRecovery, error) {
+	return c.LiveMountVMContext(context.Background(), vmName, selector, options, timeout...)
+}
+
+// LiveMountVMContext is the same as LiveMountVM with the addition of a context.Context that is used to cancel in-flight requests.
+func (c *Credentials) LiveMountVMContext(ctx context.Context, vmName string, selector SnapshotSelector, options VMRecoveryOptions, timeout ...int) (*VMRecovery, error) {
+
+	ctx, span := c.startSpan(ctx, "LiveMountVM")
+	defer span.End()
+
+	httpTimeout := httpTimeout(timeout)
+
+	if options.PreserveMOID {
+		return nil, errors.New("The 'PreserveMOID' option can only be used with InstantRecoverVM()")
+	}
+
+	config := map[string]interface{}{
+		"disableNetwork":       options.DisableNetwork,
+		"removeNetworkDevices": options.RemoveNetworkDevices,
+		"keepMacAddresses":     options.KeepMACAddresses,
+		"powerOn":              options.PowerOn,
+	}
+	if options.Datastore != "" {
+		config["datastoreName"] = options.Datastore
+	}
+
+	return c.recoverVM(ctx, "mount", vmName, selector, options, config, httpTimeout)
+}
+
+// InstantRecoverVM replaces the vSphere VM with a new VM running from the snapshot chosen by the selector (ex.
+// LatestBefore(t)). The source VM is renamed and powered off. Use Wait() to wait for the recovery to complete.
+func (c *Credentials) InstantRecoverVM(vmName string, selector SnapshotSelector, options VMRecoveryOptions, timeout ...int) (*VMRecovery, error) {
+	return c.InstantRecoverVMContext(context.Background(), vmName, selector, options, timeout...)
+}
+
+// InstantRecoverVMContext is the same as InstantRecoverVM with the addition of a context.Context that is used to cancel in-flight requests.
+func (c *Credentials) InstantRecoverVMContext(ctx context.Context, vmName string, selector SnapshotSelector, options VMRecoveryOptions, timeout ...int) (*VMRecovery, error) {
+
+	ctx, span := c.startSpan(ctx, "InstantRecoverVM")
+	defer span.End()
+
+	httpTimeout := httpTimeout(timeout)
+
+	if options.Datastore != "" {
+		return nil, errors.New("The 'Datastore' option can not be used with InstantRecoverVM()")
+	}
+	if options.DisableNetwork {
+		return nil, errors.New("The 'DisableNetwork' option can not be used with InstantRecoverVM()")
+	}
+
+	config := map[string]interface{}{
+		"removeNetworkDevices": options.RemoveNetworkDevices,
+		"keepMacAddresses":     options.KeepMACAddresses,
+		"powerOn":              options.PowerOn,
+		"preserveMoid":         options.PreserveMOID,
+	}
+
+	return c.recoverVM(ctx, "instant_recover", vmName, selector, options, config, httpTimeout)
+}
+
+// ExportVM copies the snapshot of the vSphere VM chosen by the selector to a new VM on the datastore and ESXi host
+// provided through the "Datastore" and "Host" options, which are required. Use Wait() to wait for the export to complete.
+func (c *Credentials) ExportVM(vmName string, selector SnapshotSelector, options VMRecoveryOptions, timeout ...int) (*VMRecovery, error) {
+	return c.ExportVMContext(context.Background(), vmName, selector, options, timeout...)
+}
+
+// ExportVMContext is the same as ExportVM with the addition of a context.Context that is used to cancel in-flight requests.
+func (c *Credentials) ExportVMContext(ctx context.Context, vmName string, selector SnapshotSelector, options VMRecoveryOptions, timeout ...int) (*VMRecovery, error) {
+
+	ctx, span := c.startSpan(ctx, "ExportVM")
+	defer span.End()
+
+	httpTimeout := httpTimeout(timeout)
+
+	if options.Host == "" || options.Datastore == "" {
+		return nil, errors.New("The 'Host' and 'Datastore' options are required to export a VM")
+	}
+	if options.PreserveMOID {
+		return nil, errors.New("The 'PreserveMOID' option can only be used with InstantRecoverVM()")
+	}
+
+	datastoreID, err := c.ObjectIDContext(ctx, options.Datastore, ObjectTypeVMwareDatastore, httpTimeout)
+	if err != nil {
+		return nil, err
+	}
+
+	config := map[string]interface{}{
+		"datastoreId":          datastoreID,
+		"disableNetwork":       options.DisableNetwork,
+		"removeNetworkDevices": options.RemoveNetworkDevices,
+		"keepMacAddresses":     options.KeepMACAddresses,
+		"powerOn":              options.PowerOn,
+	}
+
+	return c.recoverVM(ctx, "export", vmName, selector, options, config, httpTimeout)
+}
+
+// recoverVM selects the snapshot of the VM and starts the recovery "operation" (ex. mount) with the provided config
+// completed by the name and host of the new VM.
+func (c *Credentials) recoverVM(ctx context.Context, operation, vmName string, selector SnapshotSelector, options VMRecoveryOptions, config map[string]interface{}, httpTimeout int) (*VMRecovery, error) {
+
+	if len(options.NetworkBindings) != 0 {
+		if options.DisableNetwork || options.RemoveNetworkDevices {
+			return nil, errors.New("The 'NetworkBindings' option can not be used with the 'DisableNetwork' or 'RemoveNetworkDevices' options")
+		}
+
+		vNicBindings := []interface{}{}
+		for _, binding := range options.NetworkBindings {
+			if binding.NetworkAdapter == "" || binding.Network == "" {
+				return nil, errors.New("The 'NetworkAdapter' and 'Network' of every network binding are required")
+			}
+			vNicBindings = append(vNicBindings, map[string]interface{}{
+				"networkDeviceInfo":  map[string]interface{}{"name": binding.NetworkAdapter},
+				"backingNetworkInfo": map[string]interface{}{"name": binding.Network},
+			})
+		}
+
+		if err := c.requireCapability(ctx, CapabilityVMNetworkBinding, httpTimeout); err != nil {
+			return nil, err
+		}
+		config["vNicBindings"] = vNicBindings
+	}
+
+	vmID, err := c.ObjectIDContext(ctx, vmName, ObjectTypeVMware, httpTimeout)
+	if err != nil {
+		return nil, err
+	}
+
+	snapshots, err := c.SnapshotsContext(ctx, ObjectTypeVMware, vmID, httpTimeout)
+	if err != nil {
+		return nil, err
+	}
+
+	snapshot, found := selector.Select(snapshots)
+	if found == false {
+		return nil, newError(ErrNotFound, "The vSphere VM '%s' does not have a snapshot matching '%s'", vmName, selector)
+	}
+
+	if options.VMName != "" {
+		config["vmName"] = options.VMName
+	}
+	if options.Host != "" {
+		hostID, err := c.ObjectIDContext(ctx, options.Host, ObjectTypeVMwareHost, httpTimeout)
+		if err != nil {
+			return nil, err
+		}
+		config["hostId"] = hostID
+	}
+
+	apiRequest, err := c.PostContext(ctx, "v1", fmt.Sprintf("/vmware/vm/snapshot/%s/%s", snapshot.ID, operation), config, httpTimeout)
+	if err != nil {
+		return nil, err
+	}
+
+	job, err := c.newJob(apiRequest, httpTimeout)
+	if err != nil {
+		return nil, err
+	}
+
+	return &VMRecovery{Job: job, SnapshotID: snapshot.ID}, nil
+}
+
+// UnmountVM removes the Live Mount created by LiveMountVM(). When "force" is true, the Live Mount is removed even when
+// the mounted VM can not be deleted from vSphere.
+//
+// The function will return one of the following:
+//
+//	An error matching ErrNoChangeRequired: No change required. The Live Mount '{mountID}' does not exist
+//
+//	A Job that monitors the removal of the Live Mount. Use Wait() to wait for the removal to complete.
+func (c *Credentials) UnmountVM(mountID string, force bool, timeout ...int) (*Job, error) {
+	return c.UnmountVMContext(context.Background(), mountID, force, timeout...)
+}
+
+// UnmountVMContext is the same as UnmountVM with the addition of a context.Context that is used to cancel in-flight requests.
+func (c *Credentials) UnmountVMContext(ctx context.Context, mountID string, force bool, timeout ...int) (*Job, error) {
+
+	ctx, span := c.startSpan(ctx, "UnmountVM")
+	defer span.End()
+
+	httpTimeout := httpTimeout(timeout)
+
+	apiRequest, err := c.DeleteContext(ctx, "v1", fmt.Sprintf("/vmware/vm/snapshot/mount/%s?force=%t", mountID, force), httpTimeout)
+	if errors.Is(err, ErrNotFound) {
+		return nil, newError(ErrNoChangeRequired, "No change required. The Live Mount '%s' does not exist", mountID)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return c.newJob(apiRequest, httpTimeout)
+}
+
+// FileConflictPolicy is how RestoreFiles() handles a restored file that already exists on the target host.
+type FileConflictPolicy string
+
+const (
+	// FileConflictOverwrite replaces the existing file with the restored file.
+	FileConflictOverwrite FileConflictPolicy = "OVERWRITE"
+	// FileConflictIgnore keeps the existing file and skips the restored file.
+	FileConflictIgnore FileConflictPolicy = "IGNORE"
+	// FileConflictRename keeps the existing file and restores the file under a new name.
+	FileConflictRename FileConflictPolicy = "RENAME"
+)
+
+// FileRestoreOptions are the target and settings of RestoreFiles(). The zero value restores the files to their original
+// location and overwrites the existing files.
+type FileRestoreOptions struct {
+	// Host is the name of the alternate target, a physical host for a fileset snapshot or a vSphere VM for a VM snapshot.
+	// The files are restored to the original host or VM when it is empty
+	Host string
+	// Path is the directory the files are restored into. Each file is restored to its original directory when it is empty
+	Path string
+	// ConflictPolicy defaults to FileConflictOverwrite
+	ConflictPolicy FileConflictPolicy
+	// IgnoreErrors continues the restore when a file can not be restored
+	IgnoreErrors bool
+}
+
+// fileRestoreEndpoint is the Rubrik API endpoint that restores the files of a snapshot, along with the ObjectType of an
+// alternate target and the field of the request that identifies it.
+type fileRestoreEndpoint struct {
+	apiVersion  string
+	endpoint    string
+	targetType  ObjectType
+	targetField string
+}
+
+// fileRestoreEndpoints is the fileRestoreEndpoint of each ObjectType supported by RestoreFiles().
+var fileRestoreEndpoints = map[ObjectType]fileRestoreEndpoint{
+	ObjectTypeFileset: {apiVersion: "v1", endpoint: "/fileset/snapshot/%s/restore_files", targetType: ObjectTypePhysicalHost, targetField: "hostId"},
+	ObjectTypeVMware:  {apiVersion: "internal", endpoint: "/vmware/vm/snapshot/%s/restore_files", targetType: ObjectTypeVMware, targetField: "targetVmId"},
+}
+
+// RestoreFiles restores the files and directories at "paths" from the snapshot chosen by the selector (ex.
+// LatestBefore(t)) back to the original host, or to the alternate host and directory provided through the options. The
+// object is a fileset, ex. ObjectQuery{Type: ObjectTypeFileset, Name: "etc", Hostname: "centos-01"}, or a vSphere VM,
+// ex. ObjectQuery{Type: ObjectTypeVMware, Name: "ubuntu-01"}.
+//
+// The function will return:
+//
+//	A Job that monitors the restore. Use Wait() to wait for the restore to complete.
+func (c *Credentials) RestoreFiles(object ObjectQuery, selector SnapshotSelector, paths []string, options FileRestoreOptions, timeout ...int) (*Job, error) {
+	return c.RestoreFilesContext(context.Background(), object, selector, paths, options, timeout...)
+}
+
+// RestoreFilesContext is the same as RestoreFiles with the addition of a context.Context that is used to cancel in-flight requests.
+func (c *Credentials) RestoreFilesContext(ctx context.Context, object ObjectQuery, selector SnapshotSelector, paths []string, options FileRestoreOptions, timeout ...int) (*Job, error) {
+
+	ctx, span := c.startSpan(ctx, "RestoreFiles")
+	defer span.End()
+
+	httpTimeout := httpTimeout(timeout)
+
+	objectType, err := checkObjectType("RestoreFiles", object.Type)
+	if err != nil {
+		return nil, err
+	}
+	object.Type = objectType
+
+	if len(paths) == 0 {
+		return nil, errors.New("At least one path to restore is required")
+	}
+
+	conflictPolicy := options.ConflictPolicy
+	switch conflictPolicy {
+	case "":
+		conflictPolicy = FileConflictOverwrite
+	case FileConflictOverwrite, FileConflictIgnore, FileConflictRename:
+	default:
+		return nil, fmt.Errorf("The 'ConflictPolicy' must be %s", quotedChoices([]string{string(FileConflictOverwrite), string(FileConflictIgnore), string(FileConflictRename)}))
+	}
+
+	objectID, err := c.findObject(ctx, object, httpTimeout)
+	if err != nil {
+		return nil, err
+	}
+
+	snapshots, err := c.SnapshotsContext(ctx, objectType, objectID, httpTimeout)
+	if err != nil {
+		return nil, err
+	}
+
+	snapshot, found := selector.Select(snapshots)
+	if found == false {
+		return nil, newError(ErrNotFound, "The %s object '%s' does not have a snapshot matching '%s'", objectType, object.Name, selector)
+	}
+
+	restoreConfig := []map[string]string{}
+	for _, path := range paths {
+		restorePath := options.Path
+		if restorePath == "" {
+			restorePath = parentDirectory(path)
+		}
+		restoreConfig = append(restoreConfig, map[string]string{"path": path, "restorePath": restorePath})
+	}
+
+	endpoint := fileRestoreEndpoints[objectType]
+
+	config := map[string]interface{}{
+		"restoreConfig":      restoreConfig,
+		"conflictResolution": string(conflictPolicy),
+		"ignoreErrors":       options.IgnoreErrors,
+	}
+	if options.Host != "" {
+		targetID, err := c.findObject(ctx, ObjectQuery{Type: endpoint.targetType, Name: options.Host}, httpTimeout)
+		if err != nil {
+			return nil, err
+		}
+		config[endpoint.targetField] = targetID
+	}
+
+	apiRequest, err := c.PostContext(ctx, endpoint.apiVersion, fmt.Sprintf(endpoint.endpoint, snapshot.ID), config, httpTimeout)
+	if err != nil {
+		return nil, err
+	}
+
+	return c.newJob(apiRequest, httpTimeout)
+}
+
+// parentDirectory returns the directory containing the Linux or Windows path, ex. /etc for /etc/hosts or C:\Users for
+// C:\Users\Administrator.
+func parentDirectory(path string) string {
+
+	trimmed := strings.TrimRight(path, `/\`)
+	index := strings.LastIndexAny(trimmed, `/\`)
+	switch {
+	case index == -1:
+		return ""
+	case index == 0:
+		return trimmed[:1]
+	case trimmed[index] == '\\' && index == 2 && trimmed[1] == ':':
+		return trimmed[:index+1]
+	}
+	return trimmed[:index]
+}
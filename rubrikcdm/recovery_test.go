@@ -0,0 +1,78 @@
+// Copyright 2018 Rubrik, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License prop
+//  http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rubrikcdm_test
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/rubrikinc/rubrik-sdk-for-go/rubrikcdm"
+	"github.com/rubrikinc/rubrik-sdk-for-go/rubrikcdm/rubrikcdmtest"
+)
+
+func TestLiveMountVMNetworkBindings(t *testing.T) {
+
+	server := rubrikcdmtest.NewServer()
+	defer server.Close()
+	server.AddVM("ubuntu-01")
+	server.AddVMSnapshot("ubuntu-01", rubrikcdmtest.Snapshot{})
+
+	rubrik := server.Credentials()
+
+	bindings := []rubrikcdm.VMNetworkBinding{
+		{NetworkAdapter: "Network adapter 1", Network: "recovery-pg"},
+		{NetworkAdapter: "Network adapter 2", Network: "backup-dvpg"},
+	}
+	recovery, err := rubrik.LiveMountVM("ubuntu-01", rubrikcdm.Latest(), rubrikcdm.VMRecoveryOptions{NetworkBindings: bindings})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := recovery.Wait(); err != nil {
+		t.Fatal(err)
+	}
+
+	mounts := server.Mounts()
+	if len(mounts) != 1 || reflect.DeepEqual(mounts[0].NetworkBindings, bindings) == false {
+		t.Errorf("Mounts() = %+v, want a Live Mount with the network bindings %+v", mounts, bindings)
+	}
+}
+
+func TestRecoverVMNetworkBindingsErrors(t *testing.T) {
+
+	server := rubrikcdmtest.NewServer()
+	defer server.Close()
+	server.AddVM("ubuntu-01")
+	server.AddVMSnapshot("ubuntu-01", rubrikcdmtest.Snapshot{})
+
+	rubrik := server.Credentials()
+	bindings := []rubrikcdm.VMNetworkBinding{{NetworkAdapter: "Network adapter 1", Network: "recovery-pg"}}
+
+	invalid := []rubrikcdm.VMRecoveryOptions{
+		{NetworkBindings: bindings, DisableNetwork: true},
+		{NetworkBindings: bindings, RemoveNetworkDevices: true},
+		{NetworkBindings: []rubrikcdm.VMNetworkBinding{{NetworkAdapter: "Network adapter 1"}}},
+	}
+	for _, options := range invalid {
+		if _, err := rubrik.LiveMountVM("ubuntu-01", rubrikcdm.Latest(), options); err == nil {
+			t.Errorf("LiveMountVM() with %+v succeeded", options)
+		}
+	}
+
+	server.SetVersion("5.2.2-p3-1234")
+	_, err := rubrik.InstantRecoverVM("ubuntu-01", rubrikcdm.Latest(), rubrikcdm.VMRecoveryOptions{NetworkBindings: bindings})
+	if errors.Is(err, rubrikcdm.ErrUnsupportedVersion) == false {
+		t.Errorf("InstantRecoverVM() on CDM 5.2 = %v, want an error matching ErrUnsupportedVersion", err)
+	}
+}
@@ -0,0 +1,211 @@
+// Copyright 2018 Rubrik, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License prop
+//  http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rubrikcdm
+
+import (
+	"errors"
+	"io"
+	"math/rand"
+	"net"
+	"net/http"
+	"strconv"
+	"syscall"
+	"time"
+)
+
+// RetryPolicy controls how a Client retries API calls that fail because of a transient condition on the Rubrik cluster,
+// such as a node rebooting during an upgrade.
+//
+// GET, PUT, PATCH and DELETE calls are retried for every retryable status code and network error. Because a POST may create
+// an object or start a job, it is only retried when the Rubrik cluster could not have processed it (the connection was
+// refused, or the cluster responded with 429 Too Many Requests or 503 Service Unavailable) unless RetryPost is set.
+type RetryPolicy struct {
+	// MaxAttempts is the total number of times an API call is sent, including the first attempt. A value of 1 disables retries.
+	MaxAttempts int
+	// InitialBackoff is the wait before the first retry. The wait doubles after every attempt.
+	InitialBackoff time.Duration
+	// MaxBackoff caps the wait between two attempts.
+	MaxBackoff time.Duration
+	// RetryableStatusCodes are the HTTP status codes that are retried.
+	RetryableStatusCodes []int
+	// RetryPost retries POST calls under the same conditions as the other methods. Only enable it when every POST sent
+	// through the Client is safe to send more than once.
+	RetryPost bool
+}
+
+// DefaultRetryPolicy returns the RetryPolicy used by a Client when WithRetryPolicy() is not provided: 4 attempts with a
+// backoff starting at 1 second, capped at 30 seconds, for 429, 500, 502, 503 and 504 responses and network errors.
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		MaxAttempts:    4,
+		InitialBackoff: time.Second,
+		MaxBackoff:     30 * time.Second,
+		RetryableStatusCodes: []int{
+			http.StatusTooManyRequests,
+			http.StatusInternalServerError,
+			http.StatusBadGateway,
+			http.StatusServiceUnavailable,
+			http.StatusGatewayTimeout,
+		},
+	}
+}
+
+// WithRetryPolicy replaces the DefaultRetryPolicy() used by the Client.
+func WithRetryPolicy(policy RetryPolicy) ClientOption {
+	return func(config *clientConfig) error {
+		if policy.MaxAttempts < 1 {
+			return errors.New("The RetryPolicy 'MaxAttempts' must be at least 1")
+		}
+		config.retryPolicy = &policy
+		return nil
+	}
+}
+
+// retryPolicy returns the RetryPolicy used for every request. Credentials created through Connect() never retry.
+func (c *Credentials) retryPolicy() RetryPolicy {
+	if c.config == nil {
+		return RetryPolicy{MaxAttempts: 1}
+	}
+	if c.config.retryPolicy == nil {
+		return DefaultRetryPolicy()
+	}
+	return *c.config.retryPolicy
+}
+
+// retryResponse determines if the response to an API call should be retried and returns the wait before the next attempt.
+func (policy RetryPolicy) retryResponse(method string, attempt int, response *http.Response) (time.Duration, bool) {
+
+	if attempt >= policy.MaxAttempts {
+		return 0, false
+	}
+
+	retryable := false
+	for _, statusCode := range policy.RetryableStatusCodes {
+		if response.StatusCode == statusCode {
+			retryable = true
+		}
+	}
+	if retryable == false {
+		return 0, false
+	}
+
+	// A 429 or 503 is returned before the Rubrik cluster processes the request which makes it safe to send a POST again
+	notProcessed := response.StatusCode == http.StatusTooManyRequests || response.StatusCode == http.StatusServiceUnavailable
+	if policy.idempotent(method) == false && notProcessed == false {
+		return 0, false
+	}
+
+	wait := policy.backoff(attempt)
+	if retryAfter, ok := parseRetryAfter(response.Header.Get("Retry-After")); ok {
+		wait = retryAfter
+	}
+
+	return wait, true
+}
+
+// retryError determines if a network error should be retried and returns the wait before the next attempt.
+func (policy RetryPolicy) retryError(method string, attempt int, err error) (time.Duration, bool) {
+
+	if attempt >= policy.MaxAttempts {
+		return 0, false
+	}
+
+	// A refused connection never reached the Rubrik cluster which makes it safe to send a POST again
+	refused := connectionRefused(err)
+
+	if policy.idempotent(method) == false && refused == false {
+		return 0, false
+	}
+
+	var netErr net.Error
+	if refused || errors.As(err, &netErr) || errors.Is(err, syscall.ECONNRESET) {
+		return policy.backoff(attempt), true
+	}
+
+	return 0, false
+}
+
+// retryDownload determines if an interrupted download should be resumed and returns the wait before the next attempt.
+func (policy RetryPolicy) retryDownload(attempt int, err error) (time.Duration, bool) {
+
+	if attempt >= policy.MaxAttempts {
+		return 0, false
+	}
+
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		for _, statusCode := range policy.RetryableStatusCodes {
+			if apiErr.StatusCode == statusCode {
+				return policy.backoff(attempt), true
+			}
+		}
+		return 0, false
+	}
+
+	if errors.Is(err, io.ErrUnexpectedEOF) || errors.Is(err, errDownloadStalled) {
+		return policy.backoff(attempt), true
+	}
+
+	return policy.retryError("GET", attempt, err)
+}
+
+// connectionRefused returns true when the error occurred before the connection to the Rubrik cluster was established.
+func connectionRefused(err error) bool {
+	var opErr *net.OpError
+	return errors.Is(err, syscall.ECONNREFUSED) || (errors.As(err, &opErr) && opErr.Op == "dial")
+}
+
+// idempotent returns true when an API call using the HTTP method can safely be sent more than once.
+func (policy RetryPolicy) idempotent(method string) bool {
+	return method != "POST" || policy.RetryPost
+}
+
+// backoff returns the exponential backoff for the attempt with a random jitter of up to half the wait.
+func (policy RetryPolicy) backoff(attempt int) time.Duration {
+
+	wait := policy.InitialBackoff
+	for i := 1; i < attempt && wait < policy.MaxBackoff; i++ {
+		wait *= 2
+	}
+	if policy.MaxBackoff > 0 && wait > policy.MaxBackoff {
+		wait = policy.MaxBackoff
+	}
+	if wait <= 0 {
+		return 0
+	}
+
+	return wait/2 + time.Duration(rand.Int63n(int64(wait/2)+1))
+}
+
+// parseRetryAfter converts the value of a Retry-After header, either a number of seconds or an HTTP date, to a duration.
+func parseRetryAfter(retryAfter string) (time.Duration, bool) {
+
+	if retryAfter == "" {
+		return 0, false
+	}
+
+	if seconds, err := strconv.Atoi(retryAfter); err == nil && seconds >= 0 {
+		return time.Duration(seconds) * time.Second, true
+	}
+
+	if date, err := http.ParseTime(retryAfter); err == nil {
+		wait := time.Until(date)
+		if wait < 0 {
+			wait = 0
+		}
+		return wait, true
+	}
+
+	return 0, false
+}
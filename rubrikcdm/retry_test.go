@@ -0,0 +1,192 @@
+// Copyright 2018 Rubrik, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License prop
+//  http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rubrikcdm
+
+import (
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"sync/atomic"
+	"syscall"
+	"testing"
+	"time"
+)
+
+func TestBackoff(t *testing.T) {
+
+	policy := RetryPolicy{MaxAttempts: 10, InitialBackoff: time.Second, MaxBackoff: 5 * time.Second}
+
+	tests := []struct {
+		attempt int
+		// wait is the backoff before the jitter, the returned value is between wait/2 and wait
+		wait time.Duration
+	}{
+		{1, time.Second},
+		{2, 2 * time.Second},
+		{3, 4 * time.Second},
+		{4, 5 * time.Second},
+		{9, 5 * time.Second},
+	}
+
+	for _, test := range tests {
+		for i := 0; i < 50; i++ {
+			got := policy.backoff(test.attempt)
+			if got < test.wait/2 || got > test.wait {
+				t.Fatalf("backoff(%d) = %s, want between %s and %s", test.attempt, got, test.wait/2, test.wait)
+			}
+		}
+	}
+
+	if got := (RetryPolicy{MaxAttempts: 2}).backoff(1); got != 0 {
+		t.Errorf("backoff() without an InitialBackoff = %s, want 0", got)
+	}
+}
+
+func TestRetryResponse(t *testing.T) {
+
+	policy := DefaultRetryPolicy()
+	policy.InitialBackoff = time.Millisecond
+	policy.MaxBackoff = time.Millisecond
+
+	tests := []struct {
+		name       string
+		method     string
+		statusCode int
+		retryAfter string
+		attempt    int
+		retry      bool
+		wait       time.Duration
+	}{
+		{"retryable GET", "GET", http.StatusBadGateway, "", 1, true, -1},
+		{"success", "GET", http.StatusOK, "", 1, false, 0},
+		{"client error", "GET", http.StatusNotFound, "", 1, false, 0},
+		{"last attempt", "GET", http.StatusServiceUnavailable, "", 4, false, 0},
+		{"POST not processed", "POST", http.StatusServiceUnavailable, "", 1, true, -1},
+		{"POST throttled", "POST", http.StatusTooManyRequests, "", 1, true, -1},
+		{"POST maybe processed", "POST", http.StatusInternalServerError, "", 1, false, 0},
+		{"POST gateway timeout", "POST", http.StatusGatewayTimeout, "", 1, false, 0},
+		{"Retry-After seconds", "PUT", http.StatusTooManyRequests, "7", 1, true, 7 * time.Second},
+		{"Retry-After invalid", "DELETE", http.StatusServiceUnavailable, "soon", 1, true, -1},
+	}
+
+	for _, test := range tests {
+		response := &http.Response{StatusCode: test.statusCode, Header: http.Header{}}
+		if test.retryAfter != "" {
+			response.Header.Set("Retry-After", test.retryAfter)
+		}
+
+		wait, retry := policy.retryResponse(test.method, test.attempt, response)
+		if retry != test.retry {
+			t.Errorf("%s: retryResponse() retry = %t, want %t", test.name, retry, test.retry)
+			continue
+		}
+		// -1 is any backoff of the policy
+		if test.wait == -1 && (wait < 0 || wait > policy.MaxBackoff) || test.wait != -1 && wait != test.wait {
+			t.Errorf("%s: retryResponse() wait = %s", test.name, wait)
+		}
+	}
+
+	policy.RetryPost = true
+	if _, retry := policy.retryResponse("POST", 1, &http.Response{StatusCode: http.StatusInternalServerError}); retry == false {
+		t.Error("retryResponse() did not retry a POST with RetryPost set")
+	}
+}
+
+func TestRetryError(t *testing.T) {
+
+	policy := DefaultRetryPolicy()
+	policy.InitialBackoff = time.Millisecond
+
+	refused := &net.OpError{Op: "dial", Net: "tcp", Err: os.NewSyscallError("connect", syscall.ECONNREFUSED)}
+	reset := &net.OpError{Op: "read", Net: "tcp", Err: os.NewSyscallError("read", syscall.ECONNRESET)}
+	timeout := &net.DNSError{Err: "i/o timeout", IsTimeout: true}
+
+	tests := []struct {
+		name    string
+		method  string
+		err     error
+		attempt int
+		retry   bool
+	}{
+		{"refused GET", "GET", refused, 1, true},
+		{"refused POST", "POST", refused, 1, true},
+		{"reset GET", "GET", reset, 1, true},
+		{"reset POST", "POST", reset, 1, false},
+		{"timeout PATCH", "PATCH", timeout, 2, true},
+		{"timeout POST", "POST", timeout, 1, false},
+		{"last attempt", "GET", refused, 4, false},
+		{"not a network error", "GET", errors.New("Unable to decode the response"), 1, false},
+	}
+
+	for _, test := range tests {
+		if _, retry := policy.retryError(test.method, test.attempt, test.err); retry != test.retry {
+			t.Errorf("%s: retryError() retry = %t, want %t", test.name, retry, test.retry)
+		}
+	}
+}
+
+func TestParseRetryAfter(t *testing.T) {
+
+	if wait, ok := parseRetryAfter("120"); ok == false || wait != 2*time.Minute {
+		t.Errorf("parseRetryAfter(120) = %s, %t", wait, ok)
+	}
+
+	date := time.Now().Add(time.Minute).UTC().Format(http.TimeFormat)
+	if wait, ok := parseRetryAfter(date); ok == false || wait <= 0 || wait > time.Minute {
+		t.Errorf("parseRetryAfter(%s) = %s, %t", date, wait, ok)
+	}
+
+	for _, retryAfter := range []string{"", "-1", "later"} {
+		if _, ok := parseRetryAfter(retryAfter); ok {
+			t.Errorf("parseRetryAfter(%q) succeeded", retryAfter)
+		}
+	}
+}
+
+func TestClientRetriesTransientErrors(t *testing.T) {
+
+	var attempts int32
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&attempts, 1) < 3 {
+			w.Header().Set("Retry-After", "0")
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Write([]byte(`{"version":"9.0.1"}`))
+	}))
+	defer server.Close()
+
+	client, err := NewClient(ConnectAPIToken(strings.TrimPrefix(server.URL, "https://"), "token"), WithHTTPClient(server.Client()))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	version, err := client.ClusterVersion()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if version != "9.0.1" || attempts != 3 {
+		t.Errorf("ClusterVersion() = %s after %d attempts, want 9.0.1 after 3 attempts", version, attempts)
+	}
+
+	// Credentials created through Connect() never retry
+	atomic.StoreInt32(&attempts, 0)
+	credentials := ConnectAPIToken(strings.TrimPrefix(server.URL, "https://"), "token")
+	if _, err := credentials.ClusterVersion(); err == nil || attempts != 1 {
+		t.Errorf("ClusterVersion() without a Client = %v after %d attempts, want an error after 1 attempt", err, attempts)
+	}
+}
@@ -0,0 +1,195 @@
+// Copyright 2018 Rubrik, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License prop
+//  http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rubrikcdmtest_test
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/rubrikinc/rubrik-sdk-for-go/rubrikcdm"
+	"github.com/rubrikinc/rubrik-sdk-for-go/rubrikcdm/rubrikcdmtest"
+)
+
+func ExampleNewServer() {
+	server := rubrikcdmtest.NewServer()
+	defer server.Close()
+
+	server.AddVM("ubuntu-01")
+	server.AddSLADomain("Gold")
+
+	rubrik, err := server.NewClient()
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer rubrik.Close()
+
+	if _, err := rubrik.AssignSLA("ubuntu-01", "vmware", "Gold"); err != nil {
+		log.Fatal(err)
+	}
+
+	vm, _ := server.VM("ubuntu-01")
+	fmt.Println(vm.EffectiveSLADomainID == server.SLADomainID("Gold"))
+
+	// Output: true
+}
+
+func ExampleServer_ManagedVolume() {
+	server := rubrikcdmtest.NewServer()
+	defer server.Close()
+
+	server.AddManagedVolume("oracle-backups")
+
+	rubrik := server.Credentials()
+
+	if _, err := rubrik.BeginManagedVolumeSnapshot("oracle-backups"); err != nil {
+		log.Fatal(err)
+	}
+
+	managedVolume, _ := server.ManagedVolume("oracle-backups")
+	fmt.Println(managedVolume.Writable)
+
+	if _, err := rubrik.EndManagedVolumeSnapshot("oracle-backups", "current"); err != nil {
+		log.Fatal(err)
+	}
+
+	managedVolume, _ = server.ManagedVolume("oracle-backups")
+	fmt.Println(managedVolume.Writable, managedVolume.Snapshots)
+
+	// Output:
+	// true
+	// false 1
+}
+
+func ExampleServer_VM() {
+	server := rubrikcdmtest.NewServer()
+	defer server.Close()
+
+	server.AddVM("ubuntu-01")
+
+	rubrik := server.Credentials()
+
+	job, err := rubrik.OnDemandSnapshotVM("ubuntu-01", "vmware", "current")
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	status, err := job.Wait()
+	if err != nil {
+		log.Fatal(err)
+	}
+	fmt.Println(status.Status)
+
+	vm, _ := server.VM("ubuntu-01")
+	fmt.Println(vm.Snapshots)
+
+	// Output:
+	// SUCCEEDED
+	// 1
+}
+
+func ExampleServer_AddVMSnapshot() {
+	server := rubrikcdmtest.NewServer()
+	defer server.Close()
+
+	vmID := server.AddVM("ubuntu-01")
+	server.AddVMSnapshot("ubuntu-01", rubrikcdmtest.Snapshot{ID: "daily", Date: time.Date(2019, 4, 9, 5, 0, 0, 0, time.UTC)})
+	server.AddVMSnapshot("ubuntu-01", rubrikcdmtest.Snapshot{ID: "archived", Date: time.Date(2019, 4, 8, 5, 0, 0, 0, time.UTC), ArchivalLocationIDs: []string{"s3"}})
+
+	rubrik := server.Credentials()
+
+	snapshot, err := rubrik.SelectSnapshot(rubrikcdm.ObjectTypeVMware, vmID, rubrikcdm.Latest().Where(rubrikcdm.Archived))
+	if err != nil {
+		log.Fatal(err)
+	}
+	fmt.Println(snapshot.ID, snapshot.Date.Format(time.RFC3339))
+
+	// Output: archived 2019-04-08T05:00:00Z
+}
+
+func ExampleServer_Mounts() {
+	server := rubrikcdmtest.NewServer()
+	defer server.Close()
+
+	server.AddVM("ubuntu-01")
+	server.AddVMSnapshot("ubuntu-01", rubrikcdmtest.Snapshot{})
+
+	rubrik := server.Credentials()
+
+	recovery, err := rubrik.LiveMountVM("ubuntu-01", rubrikcdm.Latest(), rubrikcdm.VMRecoveryOptions{VMName: "ubuntu-01-mount"})
+	if err != nil {
+		log.Fatal(err)
+	}
+	if _, err := recovery.Wait(); err != nil {
+		log.Fatal(err)
+	}
+
+	mounts := server.Mounts()
+	fmt.Println(len(mounts), mounts[0].MountedVMName, mounts[0].ID == recovery.MountID())
+
+	unmount, err := rubrik.UnmountVM(recovery.MountID(), false)
+	if err != nil {
+		log.Fatal(err)
+	}
+	if _, err := unmount.Wait(); err != nil {
+		log.Fatal(err)
+	}
+	fmt.Println(len(server.Mounts()))
+
+	// Output:
+	// 1 ubuntu-01-mount true
+	// 0
+}
+
+func ExampleServer_AddDownload() {
+	server := rubrikcdmtest.NewServer()
+	defer server.Close()
+
+	rubrik, err := server.NewClient(rubrikcdm.WithRetryPolicy(rubrikcdm.RetryPolicy{MaxAttempts: 2}))
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer rubrik.Close()
+
+	jobStatusURL := server.AddDownload([]byte("127.0.0.1 localhost\n"))
+
+	// The first download request is interrupted and resumed with an HTTP Range request
+	server.SetDownloadFailures(1)
+
+	var content strings.Builder
+	written, err := rubrik.DownloadRecoveredFiles(rubrik.NewJob(jobStatusURL), &content, 0)
+	if err != nil {
+		log.Fatal(err)
+	}
+	fmt.Print(written, " ", content.String())
+
+	// Output: 20 127.0.0.1 localhost
+}
+
+func ExampleServer_HandleFunc() {
+	server := rubrikcdmtest.NewServer()
+	defer server.Close()
+
+	server.HandleFunc("GET", "/api/v1/cluster/me/version", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+		fmt.Fprint(w, `{"errorType":"server_error","message":"The cluster is upgrading","cause":null}`)
+	})
+
+	_, err := server.Credentials().ClusterVersion()
+	fmt.Println(err)
+
+	// Output: The cluster is upgrading
+}
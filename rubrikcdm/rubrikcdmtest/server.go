@@ -0,0 +1,1257 @@
+// Copyright 2018 Rubrik, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License prop
+//  http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package rubrikcdmtest provides an in-process fake Rubrik cluster that can be used to test code built on the rubrikcdm
+// package without access to a real Rubrik cluster. The fake cluster implements the API endpoints used by the SDK helper
+// functions and keeps its objects (VMs, SLA Domains, Managed Volumes, archive locations and jobs) in memory so that
+// functions such as AssignSLA() or BeginManagedVolumeSnapshot() behave as they would against a real Rubrik cluster.
+package rubrikcdmtest
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/rubrikinc/rubrik-sdk-for-go/rubrikcdm"
+)
+
+// Server is a fake Rubrik cluster served over HTTPS by an httptest.Server. Use NewServer() to create it and Close() to
+// shut it down.
+type Server struct {
+	*httptest.Server
+
+	mu           sync.Mutex
+	version      string
+	clusterID    string
+	clusterName  string
+	timezone     string
+	bootstrapped bool
+	jobPolls     int
+	jobFailure   string
+	nextID       int
+
+	vms              []*VM
+	slaDomains       []*SLADomain
+	managedVolumes   []*ManagedVolume
+	archiveLocations []*ArchiveLocation
+	snapshots        map[string][]*Snapshot
+	vmwareHosts      []*inventoryObject
+	datastores       []*inventoryObject
+	mounts           []*VMMount
+	fileRestores     []*FileRestore
+	downloads        map[string][]byte
+	downloadFailures int
+	jobs             map[string]*job
+	sessions         map[string]bool
+	requests         []string
+	handlers         map[string]http.HandlerFunc
+}
+
+// VM is a vSphere VM on the fake Rubrik cluster.
+type VM struct {
+	ID                    string
+	Name                  string
+	ConfiguredSLADomainID string
+	EffectiveSLADomainID  string
+	Paused                bool
+	Snapshots             int
+}
+
+// Snapshot is a snapshot of an object on the fake Rubrik cluster.
+type Snapshot struct {
+	ID                     string
+	Date                   time.Time
+	OnDemand               bool
+	ArchivalLocationIDs    []string
+	ReplicationLocationIDs []string
+}
+
+// VMMount is a Live Mount of a vSphere VM snapshot on the fake Rubrik cluster.
+type VMMount struct {
+	ID         string
+	SnapshotID string
+	// VMID is the ID of the VM the snapshot was taken from
+	VMID string
+	// MountedVMName is the "vmName" of the mount request
+	MountedVMName string
+	HostID        string
+	PowerOn       bool
+	// NetworkBindings are the "vNicBindings" of the mount request
+	NetworkBindings []rubrikcdm.VMNetworkBinding
+}
+
+// FileRestore is a request to restore files from a vSphere VM snapshot received by the fake Rubrik cluster.
+type FileRestore struct {
+	SnapshotID string
+	// TargetVMID is the ID of the alternate VM the files are restored to, or an empty string for the original VM
+	TargetVMID string
+	// Paths are the restored files and RestorePaths the directories they are restored into
+	Paths              []string
+	RestorePaths       []string
+	ConflictResolution string
+	IgnoreErrors       bool
+}
+
+// inventoryObject is an ESXi host or datastore, only identified by its name, on the fake Rubrik cluster.
+type inventoryObject struct {
+	id   string
+	name string
+}
+
+// SLADomain is an SLA Domain on the fake Rubrik cluster.
+type SLADomain struct {
+	ID   string
+	Name string
+	// Config is the body of the last POST or PUT /v2/sla_domain request (ex. "frequencies"), without the ID and name
+	Config map[string]interface{}
+}
+
+// ManagedVolume is a Managed Volume on the fake Rubrik cluster.
+type ManagedVolume struct {
+	ID                    string
+	Name                  string
+	ConfiguredSLADomainID string
+	EffectiveSLADomainID  string
+	Writable              bool
+	Snapshots             int
+}
+
+// ArchiveLocation is an archive location (object store) on the fake Rubrik cluster.
+type ArchiveLocation struct {
+	ID              string
+	Name            string
+	ObjectStoreType string
+	Bucket          string
+	AccessKey       string
+	DefaultRegion   string
+	StorageClass    string
+	Paused          bool
+}
+
+// job is an asynchronous request, such as an on-demand snapshot, tracked through a job status URL.
+type job struct {
+	id         string
+	polls      int
+	canceled   bool
+	failure    string
+	onComplete func()
+	// result is the href of the "result" link reported once the job has succeeded
+	result string
+}
+
+// NewServer starts a fake Rubrik cluster that has already been bootstrapped and does not contain any objects.
+func NewServer() *Server {
+
+	s := &Server{
+		version:      "5.3.0-p1-1234",
+		clusterID:    "89fd9e4c-1e8f-4b5a-8f1a-0e5f1b1e1a2b",
+		clusterName:  "rubrik-fake",
+		timezone:     "America/Los_Angeles",
+		bootstrapped: true,
+		snapshots:    map[string][]*Snapshot{},
+		downloads:    map[string][]byte{},
+		jobs:         map[string]*job{},
+		sessions:     map[string]bool{},
+		handlers:     map[string]http.HandlerFunc{},
+	}
+
+	s.Server = httptest.NewTLSServer(http.HandlerFunc(s.serveHTTP))
+
+	return s
+}
+
+// NodeIP returns the address of the fake Rubrik cluster to provide to rubrikcdm.Connect() (ex. 127.0.0.1:52411).
+func (s *Server) NodeIP() string {
+	return strings.TrimPrefix(s.URL, "https://")
+}
+
+// Credentials returns Credentials for the fake Rubrik cluster. Any username, password or API token is accepted.
+func (s *Server) Credentials() *rubrikcdm.Credentials {
+	return rubrikcdm.Connect(s.NodeIP(), "admin", "password")
+}
+
+// NewClient returns a rubrikcdm.Client that trusts the certificate of the fake Rubrik cluster. The options are appended
+// to the option that configures the HTTP client.
+func (s *Server) NewClient(options ...rubrikcdm.ClientOption) (*rubrikcdm.Client, error) {
+	return rubrikcdm.NewClient(s.Credentials(), append([]rubrikcdm.ClientOption{rubrikcdm.WithHTTPClient(s.Client())}, options...)...)
+}
+
+// SetVersion sets the CDM version returned by the fake Rubrik cluster.
+func (s *Server) SetVersion(version string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.version = version
+}
+
+// SetBootstrapped sets whether the fake Rubrik cluster has been bootstrapped.
+func (s *Server) SetBootstrapped(bootstrapped bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.bootstrapped = bootstrapped
+}
+
+// SetJobPolls sets the number of times the status of a job is reported as RUNNING before it is reported as SUCCEEDED.
+// Jobs succeed on their first poll by default.
+func (s *Server) SetJobPolls(polls int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.jobPolls = polls
+}
+
+// SetJobFailure makes the jobs started after the call finish with the FAILED status and the provided error message. An
+// empty message restores the default behavior of jobs succeeding.
+func (s *Server) SetJobFailure(message string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.jobFailure = message
+}
+
+// HandleFunc overrides the response of the fake Rubrik cluster for the HTTP method and path (ex. "GET /api/v1/cluster/me"),
+// which can be used to simulate errors.
+func (s *Server) HandleFunc(method, path string, handler http.HandlerFunc) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.handlers[method+" "+path] = handler
+}
+
+// Requests returns the method and path of every request received by the fake Rubrik cluster (ex. "GET /api/v1/vmware/vm").
+func (s *Server) Requests() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return append([]string{}, s.requests...)
+}
+
+// AddVM adds a vSphere VM that is not protected by an SLA Domain and returns its ID.
+func (s *Server) AddVM(name string) string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	vm := &VM{
+		ID:                    fmt.Sprintf("VirtualMachine:::%s-vm-%d", s.newID(), len(s.vms)+1),
+		Name:                  name,
+		ConfiguredSLADomainID: "INHERIT",
+		EffectiveSLADomainID:  "UNPROTECTED",
+	}
+	s.vms = append(s.vms, vm)
+
+	return vm.ID
+}
+
+// AddVMSnapshot adds a snapshot to the VM and returns its ID, or an empty string when the VM does not exist. The ID and
+// Date of the snapshot default to a new ID and the current time.
+func (s *Server) AddVMSnapshot(vmName string, snapshot Snapshot) string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, vm := range s.vms {
+		if vm.Name == vmName {
+			return s.addSnapshot(vm.ID, snapshot, &vm.Snapshots)
+		}
+	}
+	return ""
+}
+
+// AddVMwareHost adds an ESXi host and returns its ID.
+func (s *Server) AddVMwareHost(name string) string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	host := &inventoryObject{id: fmt.Sprintf("VmwareHost:::%s", s.newID()), name: name}
+	s.vmwareHosts = append(s.vmwareHosts, host)
+
+	return host.id
+}
+
+// AddDatastore adds a vSphere datastore and returns its ID.
+func (s *Server) AddDatastore(name string) string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	datastore := &inventoryObject{id: fmt.Sprintf("DataStore:::%s", s.newID()), name: name}
+	s.datastores = append(s.datastores, datastore)
+
+	return datastore.id
+}
+
+// AddDownload adds a succeeded file download job, such as the one started by RecoverFileDownload(), whose download link
+// serves "content" and returns its job status URL to provide to NewJob().
+func (s *Server) AddDownload(content []byte) string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	token := s.newID()
+	s.downloads[token] = append([]byte{}, content...)
+
+	id, href := s.newJob("DOWNLOAD_FILE", "v1/fileset/request", nil)
+	s.jobs[id].result = fmt.Sprintf("%s/download_dir/%s.zip", s.URL, token)
+
+	return href
+}
+
+// SetDownloadFailures makes the next "failures" download requests close the connection after sending half of the
+// file, to exercise resumed downloads.
+func (s *Server) SetDownloadFailures(failures int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.downloadFailures = failures
+}
+
+// AddSLADomain adds an SLA Domain and returns its ID.
+func (s *Server) AddSLADomain(name string) string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	slaDomain := &SLADomain{ID: s.newID(), Name: name}
+	s.slaDomains = append(s.slaDomains, slaDomain)
+
+	return slaDomain.ID
+}
+
+// AddManagedVolume adds a read-only Managed Volume and returns its ID.
+func (s *Server) AddManagedVolume(name string) string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	managedVolume := &ManagedVolume{
+		ID:                    fmt.Sprintf("ManagedVolume:::%s", s.newID()),
+		Name:                  name,
+		ConfiguredSLADomainID: "INHERIT",
+		EffectiveSLADomainID:  "UNPROTECTED",
+	}
+	s.managedVolumes = append(s.managedVolumes, managedVolume)
+
+	return managedVolume.ID
+}
+
+// VM returns the current state of the VM.
+func (s *Server) VM(name string) (VM, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, vm := range s.vms {
+		if vm.Name == name {
+			return *vm, true
+		}
+	}
+	return VM{}, false
+}
+
+// ManagedVolume returns the current state of the Managed Volume.
+func (s *Server) ManagedVolume(name string) (ManagedVolume, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, managedVolume := range s.managedVolumes {
+		if managedVolume.Name == name {
+			return *managedVolume, true
+		}
+	}
+	return ManagedVolume{}, false
+}
+
+// Mounts returns the Live Mounts of the fake Rubrik cluster.
+func (s *Server) Mounts() []VMMount {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	mounts := []VMMount{}
+	for _, mount := range s.mounts {
+		mounts = append(mounts, *mount)
+	}
+	return mounts
+}
+
+// FileRestores returns the file restore requests received by the fake Rubrik cluster.
+func (s *Server) FileRestores() []FileRestore {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	fileRestores := []FileRestore{}
+	for _, fileRestore := range s.fileRestores {
+		fileRestores = append(fileRestores, *fileRestore)
+	}
+	return fileRestores
+}
+
+// SLADomainID returns the ID of the SLA Domain or an empty string when it does not exist.
+func (s *Server) SLADomainID(name string) string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, slaDomain := range s.slaDomains {
+		if slaDomain.Name == name {
+			return slaDomain.ID
+		}
+	}
+	return ""
+}
+
+// ArchiveLocations returns the archive locations configured on the fake Rubrik cluster.
+func (s *Server) ArchiveLocations() []ArchiveLocation {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	archiveLocations := []ArchiveLocation{}
+	for _, archiveLocation := range s.archiveLocations {
+		archiveLocations = append(archiveLocations, *archiveLocation)
+	}
+	return archiveLocations
+}
+
+// newID returns a unique UUID formatted ID. The caller must hold the lock.
+func (s *Server) newID() string {
+	s.nextID++
+	return fmt.Sprintf("%08x-0000-4000-8000-%012x", s.nextID, s.nextID)
+}
+
+// addSnapshot records a snapshot of the object and increments its snapshot count. The caller must hold the lock.
+func (s *Server) addSnapshot(objectID string, snapshot Snapshot, count *int) string {
+	if snapshot.ID == "" {
+		snapshot.ID = s.newID()
+	}
+	if snapshot.Date.IsZero() {
+		snapshot.Date = time.Now()
+	}
+	s.snapshots[objectID] = append(s.snapshots[objectID], &snapshot)
+	*count++
+
+	return snapshot.ID
+}
+
+// newJob creates a job that calls onComplete once it succeeds and returns its status URL. The caller must hold the lock.
+func (s *Server) newJob(prefix, statusPath string, onComplete func()) (string, string) {
+	id := fmt.Sprintf("%s_%s:::0", prefix, s.newID())
+	s.jobs[id] = &job{id: id, polls: s.jobPolls, failure: s.jobFailure, onComplete: onComplete}
+
+	return id, fmt.Sprintf("%s/api/%s/%s", s.URL, statusPath, id)
+}
+
+// apiError is the body of an error response returned by the Rubrik cluster.
+type apiError struct {
+	ErrorType string `json:"errorType"`
+	Message   string `json:"message"`
+	Cause     string `json:"cause"`
+}
+
+func writeJSON(w http.ResponseWriter, statusCode int, body interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+	json.NewEncoder(w).Encode(body)
+}
+
+func writeError(w http.ResponseWriter, statusCode int, format string, a ...interface{}) {
+	writeJSON(w, statusCode, apiError{ErrorType: "user_error", Message: fmt.Sprintf(format, a...)})
+}
+
+// writeList writes a paginated list response using the "limit" and "offset" query parameters of the request.
+func writeList(w http.ResponseWriter, r *http.Request, items []interface{}) {
+
+	offset, _ := strconv.Atoi(r.URL.Query().Get("offset"))
+	limit, err := strconv.Atoi(r.URL.Query().Get("limit"))
+	if err != nil || limit <= 0 {
+		limit = len(items)
+	}
+
+	if offset > len(items) {
+		offset = len(items)
+	}
+	end := offset + limit
+	if end > len(items) {
+		end = len(items)
+	}
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"hasMore": end < len(items),
+		"data":    items[offset:end],
+		"total":   len(items),
+	})
+}
+
+// nameMatches emulates the "name" filter of the Rubrik list endpoints which matches any object containing the value.
+func nameMatches(r *http.Request, parameter, name string) bool {
+	filter := r.URL.Query().Get(parameter)
+	return filter == "" || strings.Contains(strings.ToLower(name), strings.ToLower(filter))
+}
+
+// serveHTTP routes the request to the handler of the API endpoint.
+func (s *Server) serveHTTP(w http.ResponseWriter, r *http.Request) {
+
+	s.mu.Lock()
+	s.requests = append(s.requests, r.Method+" "+r.URL.Path)
+	handler := s.handlers[r.Method+" "+r.URL.Path]
+	s.mu.Unlock()
+
+	if handler != nil {
+		handler(w, r)
+		return
+	}
+
+	if r.Header.Get("Authorization") == "" {
+		writeError(w, http.StatusUnauthorized, "Authentication is required")
+		return
+	}
+
+	var body map[string]interface{}
+	if r.Body != nil {
+		json.NewDecoder(r.Body).Decode(&body)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	path := strings.TrimSuffix(r.URL.Path, "/")
+	segments := strings.Split(strings.TrimPrefix(path, "/api/"), "/")
+
+	switch {
+	case r.Method == "POST" && path == "/api/v1/session":
+		token := s.newID()
+		s.sessions[token] = true
+		writeJSON(w, http.StatusOK, map[string]interface{}{"id": token, "token": token, "userId": "admin", "organizationId": "Organization:::global"})
+	case r.Method == "DELETE" && path == "/api/v1/session/me":
+		w.WriteHeader(http.StatusNoContent)
+
+	case r.Method == "GET" && (strings.Contains(path, "/request/") || strings.Contains(path, "/job/")):
+		s.serveJobStatus(w, segments[len(segments)-1])
+
+	case strings.HasPrefix(path, "/api/v1/cluster/me") || strings.HasPrefix(path, "/api/internal/cluster/me") || strings.HasPrefix(path, "/api/internal/node_management"):
+		s.serveCluster(w, r, path, body)
+
+	case len(segments) >= 4 && segments[0] == "v1" && segments[1] == "vmware" && segments[2] == "vm" && segments[3] == "snapshot":
+		s.serveVMSnapshots(w, r, segments[4:], body)
+
+	case len(segments) >= 3 && segments[0] == "v1" && segments[1] == "vmware" && segments[2] == "vm":
+		s.serveVMs(w, r, segments[3:], body)
+
+	case r.Method == "POST" && len(segments) == 6 && segments[0] == "internal" && segments[1] == "vmware" && segments[2] == "vm" && segments[3] == "snapshot" && segments[5] == "restore_files":
+		s.restoreFiles(w, segments[4], body)
+
+	case r.Method == "GET" && strings.HasPrefix(path, "/download_dir/"):
+		s.serveDownload(w, r, strings.TrimSuffix(strings.TrimPrefix(path, "/download_dir/"), ".zip"))
+
+	case r.Method == "GET" && path == "/api/v1/vmware/host":
+		writeInventory(w, r, s.vmwareHosts)
+
+	case r.Method == "GET" && path == "/api/internal/vmware/datastore":
+		writeInventory(w, r, s.datastores)
+
+	case r.Method == "GET" && (path == "/api/v1/sla_domain" || path == "/api/v2/sla_domain"):
+		items := []interface{}{}
+		for _, slaDomain := range s.slaDomains {
+			if nameMatches(r, "name", slaDomain.Name) {
+				items = append(items, s.slaDomainSummary(slaDomain))
+			}
+		}
+		writeList(w, r, items)
+	case len(segments) >= 2 && segments[0] == "v2" && segments[1] == "sla_domain":
+		s.serveSLADomains(w, r, segments[2:], body)
+	case r.Method == "POST" && len(segments) == 4 && segments[0] == "internal" && segments[1] == "sla_domain" && segments[3] == "assign":
+		s.assignSLA(w, segments[2], body)
+
+	case len(segments) >= 2 && segments[0] == "internal" && segments[1] == "managed_volume":
+		s.serveManagedVolumes(w, r, segments[2:], body)
+
+	case len(segments) >= 3 && segments[0] == "internal" && segments[1] == "archive":
+		s.serveArchiveLocations(w, r, segments[2:], body)
+
+	case r.Method == "POST" && len(segments) == 5 && segments[0] == "internal" && segments[1] == "job" && segments[2] == "instance" && segments[4] == "cancel":
+		job, ok := s.jobs[segments[3]]
+		if ok == false {
+			writeError(w, http.StatusNotFound, "Could not find job with id=%s", segments[3])
+			return
+		}
+		job.canceled = true
+		w.WriteHeader(http.StatusNoContent)
+
+	default:
+		writeError(w, http.StatusNotFound, "The fake Rubrik cluster does not implement %s %s", r.Method, r.URL.Path)
+	}
+}
+
+func (s *Server) serveCluster(w http.ResponseWriter, r *http.Request, path string, body map[string]interface{}) {
+
+	switch {
+	case r.Method == "GET" && path == "/api/v1/cluster/me/version":
+		writeJSON(w, http.StatusOK, map[string]interface{}{"version": s.version})
+	case r.Method == "GET" && path == "/api/v1/cluster/me":
+		writeJSON(w, http.StatusOK, s.clusterSummary())
+	case r.Method == "PATCH" && path == "/api/v1/cluster/me":
+		if timezone, ok := body["timezone"].(map[string]interface{}); ok {
+			s.timezone = fmt.Sprint(timezone["timezone"])
+		}
+		if name, ok := body["name"].(string); ok {
+			s.clusterName = name
+		}
+		writeJSON(w, http.StatusOK, s.clusterSummary())
+	case r.Method == "GET" && path == "/api/internal/cluster/me/node":
+		writeJSON(w, http.StatusOK, map[string]interface{}{
+			"hasMore": false,
+			"data":    []interface{}{map[string]interface{}{"id": "RVM000A000001", "brikId": "RVM000A000001", "status": "OK", "ipAddress": s.NodeIP(), "needsInspection": false}},
+			"total":   1,
+		})
+	case r.Method == "GET" && path == "/api/internal/node_management/is_bootstrapped":
+		writeJSON(w, http.StatusOK, map[string]interface{}{"value": s.bootstrapped})
+	case r.Method == "POST" && path == "/api/internal/cluster/me/bootstrap":
+		if s.bootstrapped {
+			writeError(w, http.StatusUnprocessableEntity, "The Rubrik cluster has already been bootstrapped")
+			return
+		}
+		if name, ok := body["name"].(string); ok {
+			s.clusterName = name
+		}
+		s.bootstrapped = true
+		writeJSON(w, http.StatusAccepted, map[string]interface{}{"id": 1, "status": "IN_PROGRESS"})
+	case r.Method == "GET" && path == "/api/internal/cluster/me/bootstrap":
+		status := "IN_PROGRESS"
+		if s.bootstrapped {
+			status = "SUCCESS"
+		}
+		writeJSON(w, http.StatusOK, map[string]interface{}{"id": 1, "status": status, "message": "", "setupEncryptionAtRest": status})
+	default:
+		writeError(w, http.StatusNotFound, "The fake Rubrik cluster does not implement %s %s", r.Method, r.URL.Path)
+	}
+}
+
+func (s *Server) clusterSummary() map[string]interface{} {
+	return map[string]interface{}{
+		"id":                  s.clusterID,
+		"version":             s.version,
+		"apiVersion":          "1",
+		"name":                s.clusterName,
+		"timezone":            map[string]interface{}{"timezone": s.timezone},
+		"geolocation":         map[string]interface{}{"address": ""},
+		"acceptedEulaVersion": "1.0",
+		"latestEulaVersion":   "1.0",
+	}
+}
+
+// slaDomainName returns the name of the SLA Domain or the special SLA ID (ex. UNPROTECTED). The caller must hold the lock.
+func (s *Server) slaDomainName(slaID string) string {
+	for _, slaDomain := range s.slaDomains {
+		if slaDomain.ID == slaID {
+			return slaDomain.Name
+		}
+	}
+	return slaID
+}
+
+func (s *Server) vmSummary(vm *VM) map[string]interface{} {
+	return map[string]interface{}{
+		"id":                        vm.ID,
+		"name":                      vm.Name,
+		"configuredSlaDomainId":     vm.ConfiguredSLADomainID,
+		"configuredSlaDomainName":   s.slaDomainName(vm.ConfiguredSLADomainID),
+		"effectiveSlaDomainId":      vm.EffectiveSLADomainID,
+		"effectiveSlaDomainName":    s.slaDomainName(vm.EffectiveSLADomainID),
+		"primaryClusterId":          s.clusterID,
+		"isRelic":                   false,
+		"snapshotCount":             vm.Snapshots,
+		"blackoutWindowStatus":      map[string]interface{}{"isGlobalBlackoutActive": false, "isSnappableBlackoutActive": vm.Paused},
+		"isArrayIntegrationEnabled": false,
+	}
+}
+
+func snapshotSummary(snapshot *Snapshot) map[string]interface{} {
+	archivalLocationIDs := append([]string{}, snapshot.ArchivalLocationIDs...)
+	replicationLocationIDs := append([]string{}, snapshot.ReplicationLocationIDs...)
+
+	return map[string]interface{}{
+		"id":                     snapshot.ID,
+		"date":                   snapshot.Date.UTC().Format("2006-01-02T15:04:05.000Z"),
+		"isOnDemandSnapshot":     snapshot.OnDemand,
+		"archivalLocationIds":    archivalLocationIDs,
+		"replicationLocationIds": replicationLocationIDs,
+		"cloudState":             0,
+		"consistencyLevel":       "CRASH_CONSISTENT",
+		"indexState":             1,
+	}
+}
+
+func (s *Server) serveVMs(w http.ResponseWriter, r *http.Request, segments []string, body map[string]interface{}) {
+
+	if len(segments) == 0 {
+		items := []interface{}{}
+		for _, vm := range s.vms {
+			slaFilter := r.URL.Query().Get("effective_sla_domain_id")
+			if nameMatches(r, "name", vm.Name) && (slaFilter == "" || slaFilter == vm.EffectiveSLADomainID) {
+				items = append(items, s.vmSummary(vm))
+			}
+		}
+		writeList(w, r, items)
+		return
+	}
+
+	var vm *VM
+	for _, v := range s.vms {
+		if v.ID == segments[0] {
+			vm = v
+		}
+	}
+	if vm == nil {
+		writeError(w, http.StatusNotFound, "Could not find VirtualMachine with id=%s", segments[0])
+		return
+	}
+
+	switch {
+	case r.Method == "GET" && len(segments) == 1:
+		writeJSON(w, http.StatusOK, s.vmSummary(vm))
+	case r.Method == "PATCH" && len(segments) == 1:
+		if paused, ok := body["isVmPaused"].(bool); ok {
+			vm.Paused = paused
+		}
+		writeJSON(w, http.StatusOK, s.vmSummary(vm))
+	case r.Method == "GET" && len(segments) == 2 && segments[1] == "snapshot":
+		items := []interface{}{}
+		for _, snapshot := range s.snapshots[vm.ID] {
+			items = append(items, snapshotSummary(snapshot))
+		}
+		writeList(w, r, items)
+	case r.Method == "POST" && len(segments) == 2 && segments[1] == "snapshot":
+		id, href := s.newJob("CREATE_VMWARE_SNAPSHOT_"+vm.ID, "v1/vmware/vm/request", func() {
+			s.addSnapshot(vm.ID, Snapshot{OnDemand: true}, &vm.Snapshots)
+		})
+		writeJSON(w, http.StatusAccepted, map[string]interface{}{
+			"id":     id,
+			"status": "QUEUED",
+			"links":  []interface{}{map[string]interface{}{"href": href, "rel": "self"}},
+		})
+	default:
+		writeError(w, http.StatusNotFound, "The fake Rubrik cluster does not implement %s %s", r.Method, r.URL.Path)
+	}
+}
+
+func writeInventory(w http.ResponseWriter, r *http.Request, objects []*inventoryObject) {
+	items := []interface{}{}
+	for _, object := range objects {
+		if nameMatches(r, "name", object.name) {
+			items = append(items, map[string]interface{}{"id": object.id, "name": object.name})
+		}
+	}
+	writeList(w, r, items)
+}
+
+func writeAsyncRequest(w http.ResponseWriter, id, href string) {
+	writeJSON(w, http.StatusAccepted, map[string]interface{}{
+		"id":     id,
+		"status": "QUEUED",
+		"links":  []interface{}{map[string]interface{}{"href": href, "rel": "self"}},
+	})
+}
+
+// serveVMSnapshots serves the /v1/vmware/vm/snapshot endpoints that recover a VM snapshot and remove Live Mounts.
+func (s *Server) serveVMSnapshots(w http.ResponseWriter, r *http.Request, segments []string, body map[string]interface{}) {
+
+	if r.Method == "DELETE" && len(segments) == 2 && segments[0] == "mount" {
+		for _, mount := range s.mounts {
+			if mount.ID == segments[1] {
+				mountID := mount.ID
+				id, href := s.newJob("UNMOUNT_SNAPSHOT_"+mountID, "v1/vmware/vm/request", func() {
+					mounts := []*VMMount{}
+					for _, mount := range s.mounts {
+						if mount.ID != mountID {
+							mounts = append(mounts, mount)
+						}
+					}
+					s.mounts = mounts
+				})
+				writeAsyncRequest(w, id, href)
+				return
+			}
+		}
+		writeError(w, http.StatusNotFound, "Could not find Live Mount with id=%s", segments[1])
+		return
+	}
+
+	if r.Method != "POST" || len(segments) != 2 {
+		writeError(w, http.StatusNotFound, "The fake Rubrik cluster does not implement %s %s", r.Method, r.URL.Path)
+		return
+	}
+
+	vmID := s.snapshotObjectID(segments[0])
+	if vmID == "" {
+		writeError(w, http.StatusNotFound, "Could not find VmwareVirtualMachineSnapshot with id=%s", segments[0])
+		return
+	}
+
+	vmName, _ := body["vmName"].(string)
+	hostID, _ := body["hostId"].(string)
+	powerOn, _ := body["powerOn"].(bool)
+
+	var networkBindings []rubrikcdm.VMNetworkBinding
+	vNicBindings, _ := body["vNicBindings"].([]interface{})
+	for _, vNicBinding := range vNicBindings {
+		vNicBinding, _ := vNicBinding.(map[string]interface{})
+		networkDeviceInfo, _ := vNicBinding["networkDeviceInfo"].(map[string]interface{})
+		backingNetworkInfo, _ := vNicBinding["backingNetworkInfo"].(map[string]interface{})
+		binding := rubrikcdm.VMNetworkBinding{}
+		binding.NetworkAdapter, _ = networkDeviceInfo["name"].(string)
+		binding.Network, _ = backingNetworkInfo["name"].(string)
+		networkBindings = append(networkBindings, binding)
+	}
+
+	switch segments[1] {
+	case "mount":
+		mount := &VMMount{ID: s.newID(), SnapshotID: segments[0], VMID: vmID, MountedVMName: vmName, HostID: hostID, PowerOn: powerOn, NetworkBindings: networkBindings}
+		id, href := s.newJob("MOUNT_SNAPSHOT_"+vmID, "v1/vmware/vm/request", func() { s.mounts = append(s.mounts, mount) })
+		s.jobs[id].result = fmt.Sprintf("%s/api/v1/vmware/vm/snapshot/mount/%s", s.URL, mount.ID)
+		writeAsyncRequest(w, id, href)
+	case "instant_recover":
+		id, href := s.newJob("INSTANT_RECOVER_SNAPSHOT_"+vmID, "v1/vmware/vm/request", nil)
+		writeAsyncRequest(w, id, href)
+	case "export":
+		if _, ok := body["datastoreId"].(string); ok == false {
+			writeError(w, http.StatusBadRequest, "The datastoreId is required")
+			return
+		}
+		id, href := s.newJob("EXPORT_SNAPSHOT_"+vmID, "v1/vmware/vm/request", func() {
+			s.vms = append(s.vms, &VM{
+				ID:                    fmt.Sprintf("VirtualMachine:::%s-vm-%d", s.newID(), len(s.vms)+1),
+				Name:                  vmName,
+				ConfiguredSLADomainID: "INHERIT",
+				EffectiveSLADomainID:  "UNPROTECTED",
+			})
+		})
+		writeAsyncRequest(w, id, href)
+	default:
+		writeError(w, http.StatusNotFound, "The fake Rubrik cluster does not implement %s %s", r.Method, r.URL.Path)
+	}
+}
+
+func (s *Server) restoreFiles(w http.ResponseWriter, snapshotID string, body map[string]interface{}) {
+
+	if s.snapshotObjectID(snapshotID) == "" {
+		writeError(w, http.StatusNotFound, "Could not find VmwareVirtualMachineSnapshot with id=%s", snapshotID)
+		return
+	}
+
+	fileRestore := &FileRestore{SnapshotID: snapshotID}
+	fileRestore.TargetVMID, _ = body["targetVmId"].(string)
+	fileRestore.ConflictResolution, _ = body["conflictResolution"].(string)
+	fileRestore.IgnoreErrors, _ = body["ignoreErrors"].(bool)
+
+	restoreConfig, _ := body["restoreConfig"].([]interface{})
+	if len(restoreConfig) == 0 {
+		writeError(w, http.StatusBadRequest, "The restoreConfig is required")
+		return
+	}
+	for _, entry := range restoreConfig {
+		file, _ := entry.(map[string]interface{})
+		fileRestore.Paths = append(fileRestore.Paths, fmt.Sprint(file["path"]))
+		fileRestore.RestorePaths = append(fileRestore.RestorePaths, fmt.Sprint(file["restorePath"]))
+	}
+	s.fileRestores = append(s.fileRestores, fileRestore)
+
+	id, href := s.newJob("RESTORE_FILE_"+snapshotID, "internal/vmware/vm/request", nil)
+	writeAsyncRequest(w, id, href)
+}
+
+// snapshotObjectID returns the ID of the object the snapshot was taken from, or an empty string when it does not exist.
+// The caller must hold the lock.
+func (s *Server) snapshotObjectID(snapshotID string) string {
+	for objectID, snapshots := range s.snapshots {
+		for _, snapshot := range snapshots {
+			if snapshot.ID == snapshotID {
+				return objectID
+			}
+		}
+	}
+	return ""
+}
+
+// serveDownload serves the content of a download link, including HTTP Range requests.
+func (s *Server) serveDownload(w http.ResponseWriter, r *http.Request, token string) {
+
+	content, ok := s.downloads[token]
+	if ok == false {
+		writeError(w, http.StatusNotFound, "Could not find the file %s", r.URL.Path)
+		return
+	}
+
+	if s.downloadFailures > 0 {
+		s.downloadFailures--
+		// Send half of the requested range and close the connection before the announced Content-Length is reached
+		w = &truncatedWriter{ResponseWriter: w, remaining: len(content) / 2}
+	}
+
+	http.ServeContent(w, r, token+".zip", time.Time{}, bytes.NewReader(content))
+}
+
+// truncatedWriter stops writing the response body after "remaining" bytes.
+type truncatedWriter struct {
+	http.ResponseWriter
+	remaining int
+}
+
+func (w *truncatedWriter) Write(p []byte) (int, error) {
+	if len(p) > w.remaining {
+		p = p[:w.remaining]
+	}
+	w.remaining -= len(p)
+	n, err := w.ResponseWriter.Write(p)
+	if err == nil && w.remaining == 0 {
+		return n, errors.New("The download was interrupted")
+	}
+	return n, err
+}
+
+func (s *Server) serveSLADomains(w http.ResponseWriter, r *http.Request, segments []string, body map[string]interface{}) {
+
+	if r.Method == "POST" && len(segments) == 0 {
+		name, _ := body["name"].(string)
+		if name == "" {
+			writeError(w, http.StatusBadRequest, "The SLA Domain name is required")
+			return
+		}
+		for _, slaDomain := range s.slaDomains {
+			if slaDomain.Name == name {
+				writeError(w, http.StatusBadRequest, "An SLA Domain named '%s' already exists", name)
+				return
+			}
+		}
+		slaDomain := &SLADomain{ID: s.newID(), Name: name, Config: slaDomainConfig(body)}
+		s.slaDomains = append(s.slaDomains, slaDomain)
+		writeJSON(w, http.StatusCreated, s.slaDomainSummary(slaDomain))
+		return
+	}
+
+	if len(segments) != 1 {
+		writeError(w, http.StatusNotFound, "The fake Rubrik cluster does not implement %s %s", r.Method, r.URL.Path)
+		return
+	}
+
+	index := -1
+	for i, slaDomain := range s.slaDomains {
+		if slaDomain.ID == segments[0] {
+			index = i
+		}
+	}
+	if index == -1 {
+		writeError(w, http.StatusNotFound, "Could not find SLA Domain with id=%s", segments[0])
+		return
+	}
+	slaDomain := s.slaDomains[index]
+
+	switch r.Method {
+	case "GET":
+		writeJSON(w, http.StatusOK, s.slaDomainSummary(slaDomain))
+	case "PUT":
+		if name, _ := body["name"].(string); name != "" {
+			slaDomain.Name = name
+		}
+		slaDomain.Config = slaDomainConfig(body)
+		writeJSON(w, http.StatusOK, s.slaDomainSummary(slaDomain))
+	case "DELETE":
+		s.slaDomains = append(s.slaDomains[:index], s.slaDomains[index+1:]...)
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		writeError(w, http.StatusNotFound, "The fake Rubrik cluster does not implement %s %s", r.Method, r.URL.Path)
+	}
+}
+
+// slaDomainSummary returns the SLA Domain as it is returned by the Rubrik API. The caller must hold the lock.
+func (s *Server) slaDomainSummary(slaDomain *SLADomain) map[string]interface{} {
+
+	summary := map[string]interface{}{}
+	for key, value := range slaDomain.Config {
+		summary[key] = value
+	}
+	summary["id"] = slaDomain.ID
+	summary["name"] = slaDomain.Name
+	summary["primaryClusterId"] = s.clusterID
+
+	return summary
+}
+
+// slaDomainConfig returns the body of a create or update SLA Domain request without its ID and name.
+func slaDomainConfig(body map[string]interface{}) map[string]interface{} {
+
+	config := map[string]interface{}{}
+	for key, value := range body {
+		if key != "id" && key != "name" {
+			config[key] = value
+		}
+	}
+
+	return config
+}
+
+func (s *Server) assignSLA(w http.ResponseWriter, slaID string, body map[string]interface{}) {
+
+	if slaID != "UNPROTECTED" && slaID != "INHERIT" && s.slaDomainName(slaID) == slaID {
+		writeError(w, http.StatusNotFound, "Could not find SLA Domain with id=%s", slaID)
+		return
+	}
+
+	// The fake Rubrik cluster does not model the SLA Domain inherited from a parent object
+	effectiveSLAID := slaID
+	if slaID == "INHERIT" {
+		effectiveSLAID = "UNPROTECTED"
+	}
+
+	managedIDs, _ := body["managedIds"].([]interface{})
+	for _, managedID := range managedIDs {
+		for _, vm := range s.vms {
+			if vm.ID == managedID {
+				vm.ConfiguredSLADomainID = slaID
+				vm.EffectiveSLADomainID = effectiveSLAID
+			}
+		}
+		for _, managedVolume := range s.managedVolumes {
+			if managedVolume.ID == managedID {
+				managedVolume.ConfiguredSLADomainID = slaID
+				managedVolume.EffectiveSLADomainID = effectiveSLAID
+			}
+		}
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Server) serveManagedVolumes(w http.ResponseWriter, r *http.Request, segments []string, body map[string]interface{}) {
+
+	summary := func(managedVolume *ManagedVolume) map[string]interface{} {
+		return map[string]interface{}{
+			"id":                    managedVolume.ID,
+			"name":                  managedVolume.Name,
+			"isWritable":            managedVolume.Writable,
+			"isRelic":               false,
+			"snapshotCount":         managedVolume.Snapshots,
+			"configuredSlaDomainId": managedVolume.ConfiguredSLADomainID,
+			"effectiveSlaDomainId":  managedVolume.EffectiveSLADomainID,
+			"primaryClusterId":      s.clusterID,
+		}
+	}
+
+	if len(segments) == 0 {
+		items := []interface{}{}
+		for _, managedVolume := range s.managedVolumes {
+			if nameMatches(r, "name", managedVolume.Name) {
+				items = append(items, summary(managedVolume))
+			}
+		}
+		writeList(w, r, items)
+		return
+	}
+
+	var managedVolume *ManagedVolume
+	for _, mv := range s.managedVolumes {
+		if mv.ID == segments[0] {
+			managedVolume = mv
+		}
+	}
+	if managedVolume == nil {
+		writeError(w, http.StatusNotFound, "Could not find ManagedVolume with id=%s", segments[0])
+		return
+	}
+
+	switch {
+	case r.Method == "GET" && len(segments) == 1:
+		writeJSON(w, http.StatusOK, summary(managedVolume))
+	case r.Method == "POST" && len(segments) == 2 && segments[1] == "begin_snapshot":
+		if managedVolume.Writable {
+			writeError(w, http.StatusBadRequest, "The Managed Volume is already in a writable state")
+			return
+		}
+		managedVolume.Writable = true
+		w.WriteHeader(http.StatusNoContent)
+	case r.Method == "POST" && len(segments) == 2 && segments[1] == "end_snapshot":
+		if managedVolume.Writable == false {
+			writeError(w, http.StatusBadRequest, "The Managed Volume is not in a writable state")
+			return
+		}
+		slaID := "UNPROTECTED"
+		if retentionConfig, ok := body["retentionConfig"].(map[string]interface{}); ok {
+			slaID = fmt.Sprint(retentionConfig["slaId"])
+		}
+		managedVolume.Writable = false
+		managedVolume.Snapshots++
+		snapshotID := s.newID()
+		writeJSON(w, http.StatusCreated, map[string]interface{}{
+			"id":                     snapshotID,
+			"date":                   time.Now().UTC().Format(time.RFC3339),
+			"sourceObjectType":       "ManagedVolume",
+			"isOnDemandSnapshot":     true,
+			"consistencyLevel":       "UNKNOWN",
+			"replicationLocationIds": []string{},
+			"archivalLocationIds":    []string{},
+			"slaId":                  slaID,
+			"slaName":                s.slaDomainName(slaID),
+			"links": map[string]interface{}{
+				"self": map[string]interface{}{"href": fmt.Sprintf("%s/api/internal/managed_volume/snapshot/%s", s.URL, snapshotID), "rel": "self"},
+			},
+		})
+	default:
+		writeError(w, http.StatusNotFound, "The fake Rubrik cluster does not implement %s %s", r.Method, r.URL.Path)
+	}
+}
+
+func (s *Server) serveArchiveLocations(w http.ResponseWriter, r *http.Request, segments []string, body map[string]interface{}) {
+
+	definition := func(archiveLocation *ArchiveLocation) map[string]interface{} {
+		return map[string]interface{}{
+			"objectStoreType": archiveLocation.ObjectStoreType,
+			"name":            archiveLocation.Name,
+			"accessKey":       archiveLocation.AccessKey,
+			"bucket":          archiveLocation.Bucket,
+			"defaultRegion":   archiveLocation.DefaultRegion,
+			"storageClass":    archiveLocation.StorageClass,
+		}
+	}
+
+	find := func(id string) *ArchiveLocation {
+		for _, archiveLocation := range s.archiveLocations {
+			if archiveLocation.ID == id {
+				return archiveLocation
+			}
+		}
+		return nil
+	}
+
+	switch {
+	case r.Method == "GET" && len(segments) == 1 && segments[0] == "object_store":
+		items := []interface{}{}
+		for _, archiveLocation := range s.archiveLocations {
+			items = append(items, map[string]interface{}{"id": archiveLocation.ID, "definition": definition(archiveLocation)})
+		}
+		writeList(w, r, items)
+	case r.Method == "GET" && len(segments) == 1 && segments[0] == "location":
+		items := []interface{}{}
+		for _, archiveLocation := range s.archiveLocations {
+			if nameMatches(r, "name", archiveLocation.Name) {
+				items = append(items, map[string]interface{}{
+					"id":              archiveLocation.ID,
+					"name":            archiveLocation.Name,
+					"locationType":    archiveLocation.ObjectStoreType,
+					"isActive":        archiveLocation.Paused == false,
+					"bucket":          archiveLocation.Bucket,
+					"ownershipStatus": "Owner",
+				})
+			}
+		}
+		writeList(w, r, items)
+	case r.Method == "POST" && len(segments) == 1 && segments[0] == "object_store":
+		archiveLocation := &ArchiveLocation{ID: fmt.Sprintf("DataLocation:::%s", s.newID())}
+		archiveLocation.Name = fmt.Sprint(body["name"])
+		archiveLocation.ObjectStoreType = fmt.Sprint(body["objectStoreType"])
+		archiveLocation.Bucket = fmt.Sprint(body["bucket"])
+		archiveLocation.AccessKey = fmt.Sprint(body["accessKey"])
+		archiveLocation.DefaultRegion = fmt.Sprint(body["defaultRegion"])
+		archiveLocation.StorageClass = fmt.Sprint(body["storageClass"])
+		id, _ := s.newJob("ARCHIVAL_LOCATION_CONNECT", "internal/archive/location/job/connect", func() {
+			s.archiveLocations = append(s.archiveLocations, archiveLocation)
+		})
+		writeJSON(w, http.StatusAccepted, map[string]interface{}{"jobInstanceId": id})
+	case r.Method == "PATCH" && len(segments) == 2 && segments[0] == "object_store":
+		archiveLocation := find(segments[1])
+		if archiveLocation == nil {
+			writeError(w, http.StatusNotFound, "Could not find archive location with id=%s", segments[1])
+			return
+		}
+		for field, value := range map[string]*string{"name": &archiveLocation.Name, "bucket": &archiveLocation.Bucket, "accessKey": &archiveLocation.AccessKey, "defaultRegion": &archiveLocation.DefaultRegion, "storageClass": &archiveLocation.StorageClass} {
+			if v, ok := body[field].(string); ok {
+				*value = v
+			}
+		}
+		writeJSON(w, http.StatusOK, map[string]interface{}{"id": archiveLocation.ID, "definition": definition(archiveLocation)})
+	case r.Method == "POST" && len(segments) == 4 && segments[0] == "location" && segments[2] == "owner" && segments[3] == "pause":
+		archiveLocation := find(segments[1])
+		if archiveLocation == nil {
+			writeError(w, http.StatusNotFound, "Could not find archive location with id=%s", segments[1])
+			return
+		}
+		if archiveLocation.Paused {
+			writeError(w, http.StatusBadRequest, "The archive location is already paused")
+			return
+		}
+		archiveLocation.Paused = true
+		w.WriteHeader(http.StatusNoContent)
+	case r.Method == "DELETE" && len(segments) == 2 && segments[0] == "location":
+		if find(segments[1]) == nil {
+			writeError(w, http.StatusNotFound, "Could not find archive location with id=%s", segments[1])
+			return
+		}
+		archiveID := segments[1]
+		id, href := s.newJob("ARCHIVAL_LOCATION_DELETE", "internal/archive/location/job", func() {
+			for i, archiveLocation := range s.archiveLocations {
+				if archiveLocation.ID == archiveID {
+					s.archiveLocations = append(s.archiveLocations[:i], s.archiveLocations[i+1:]...)
+					break
+				}
+			}
+		})
+		writeJSON(w, http.StatusAccepted, map[string]interface{}{"id": id, "status": "QUEUED", "links": []interface{}{map[string]interface{}{"href": href, "rel": "self"}}})
+	default:
+		writeError(w, http.StatusNotFound, "The fake Rubrik cluster does not implement %s %s", r.Method, r.URL.Path)
+	}
+}
+
+// serveJobStatus reports the job as RUNNING for the configured number of polls and then completes it.
+func (s *Server) serveJobStatus(w http.ResponseWriter, id string) {
+
+	job, ok := s.jobs[id]
+	if ok == false {
+		writeError(w, http.StatusNotFound, "Could not find job with id=%s", id)
+		return
+	}
+
+	status := map[string]interface{}{"id": job.id, "nodeId": "RVM000A000001", "startTime": time.Now().UTC().Format(time.RFC3339)}
+
+	if job.canceled {
+		status["status"] = "CANCELED"
+		status["endTime"] = time.Now().UTC().Format(time.RFC3339)
+		writeJSON(w, http.StatusOK, status)
+		return
+	}
+
+	if job.polls > 0 {
+		job.polls--
+		status["status"] = "RUNNING"
+		status["progress"] = 50
+		writeJSON(w, http.StatusOK, status)
+		return
+	}
+
+	if job.failure != "" {
+		status["status"] = "FAILED"
+		status["endTime"] = time.Now().UTC().Format(time.RFC3339)
+		status["error"] = map[string]interface{}{"message": job.failure}
+		writeJSON(w, http.StatusOK, status)
+		return
+	}
+
+	if job.onComplete != nil {
+		job.onComplete()
+		job.onComplete = nil
+	}
+
+	status["status"] = "SUCCEEDED"
+	status["progress"] = 100
+	status["endTime"] = time.Now().UTC().Format(time.RFC3339)
+	if job.result != "" {
+		status["links"] = []interface{}{map[string]interface{}{"href": job.result, "rel": "result"}}
+	}
+	writeJSON(w, http.StatusOK, status)
+}
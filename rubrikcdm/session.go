@@ -0,0 +1,168 @@
+// Copyright 2018 Rubrik, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License prop
+//  http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rubrikcdm
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// session corresponds to POST /v1/session
+type session struct {
+	ID             string `json:"id"`
+	OrganizationID string `json:"organizationId"`
+	Token          string `json:"token"`
+	UserID         string `json:"userId"`
+}
+
+// usesSession returns true when the username and password are exchanged for a session token instead of being sent
+// with every request. Only Credentials created through NewClient() use session authentication.
+func (c *Credentials) usesSession() bool {
+	return c.config != nil && c.config.basicAuth == false && len(c.Username) != 0
+}
+
+// authorize adds the Authorization header to the request.
+func (c *Credentials) authorize(ctx context.Context, request *http.Request, timeout int) error {
+
+	if c.usesSession() {
+		token, err := c.sessionToken(ctx, request.URL.Host, timeout)
+		if err != nil {
+			return err
+		}
+		request.Header.Set("Authorization", fmt.Sprintf("Bearer %s", token))
+		return nil
+	}
+
+	if len(c.Username) != 0 {
+		request.SetBasicAuth(c.Username, c.Password)
+	} else {
+		request.Header.Set("Authorization", fmt.Sprintf("Bearer %s", c.APIToken))
+	}
+
+	return nil
+}
+
+// sessionToken returns the cached session token or creates a new session through the provided node when one does not exist.
+func (c *Credentials) sessionToken(ctx context.Context, nodeIP string, timeout int) (string, error) {
+
+	c.config.sessionMu.Lock()
+	defer c.config.sessionMu.Unlock()
+
+	if c.config.sessionToken != "" {
+		return c.config.sessionToken, nil
+	}
+
+	request, err := http.NewRequestWithContext(ctx, "POST", fmt.Sprintf("https://%s/api/v1/session", nodeIP), strings.NewReader("{}"))
+	if err != nil {
+		return "", err
+	}
+	request.SetBasicAuth(c.Username, c.Password)
+	request.Header.Set("Content-Type", "application/json")
+	request.Header.Set("Accept", "application/json")
+	request.Header.Set("User-Agent", userAgent)
+
+	start := time.Now()
+	apiRequest, err := c.httpClient(timeout).Do(request)
+	if err != nil {
+		c.logRequest(ctx, request, []byte("{}"), nil, nil, 1, time.Since(start), err)
+		if ctx.Err() != nil {
+			return "", ctx.Err()
+		}
+		return "", err
+	}
+	defer apiRequest.Body.Close()
+
+	body, err := ioutil.ReadAll(apiRequest.Body)
+	c.logRequest(ctx, request, []byte("{}"), apiRequest, body, 1, time.Since(start), err)
+	if err != nil {
+		return "", err
+	}
+
+	if apiRequest.StatusCode != http.StatusOK {
+		apiError := &APIError{
+			StatusCode: apiRequest.StatusCode,
+			Method:     request.Method,
+			Endpoint:   request.URL.RequestURI(),
+		}
+		var apiResponse map[string]interface{}
+		if json.Unmarshal(body, &apiResponse) == nil && apiResponse["message"] != nil {
+			apiError.ErrorType = fmt.Sprint(apiResponse["errorType"])
+			apiError.Message = fmt.Sprintf("Unable to create a session on the Rubrik cluster: %s", apiResponse["message"])
+		} else {
+			apiError.Message = fmt.Sprintf("Unable to create a session on the Rubrik cluster: %s", apiRequest.Status)
+		}
+		return "", apiError
+	}
+
+	var apiResponse session
+	if err := json.Unmarshal(body, &apiResponse); err != nil {
+		return "", err
+	}
+
+	if apiResponse.Token == "" {
+		return "", errors.New("Unable to create a session on the Rubrik cluster: the API response did not contain a token")
+	}
+
+	c.config.sessionToken = apiResponse.Token
+
+	return c.config.sessionToken, nil
+}
+
+// clearSession removes the cached session token so that the next request creates a new session.
+func (config *clientConfig) clearSession() {
+	config.sessionMu.Lock()
+	config.sessionToken = ""
+	config.sessionMu.Unlock()
+}
+
+// Logout deletes the session created on the Rubrik cluster by a Client. Subsequent requests will create a new session.
+// When no session has been created, Logout does nothing.
+func (c *Credentials) Logout(timeout ...int) error {
+	return c.LogoutContext(context.Background(), timeout...)
+}
+
+// LogoutContext is the same as Logout with the addition of a context.Context that is used to cancel in-flight requests.
+func (c *Credentials) LogoutContext(ctx context.Context, timeout ...int) error {
+
+	if c.usesSession() == false {
+		return nil
+	}
+
+	c.config.sessionMu.Lock()
+	activeSession := c.config.sessionToken != ""
+	c.config.sessionMu.Unlock()
+
+	if activeSession == false {
+		return nil
+	}
+
+	httpTimeout := httpTimeout(timeout)
+
+	_, err := c.DeleteContext(ctx, "v1", "/session/me", httpTimeout)
+
+	c.config.clearSession()
+
+	return err
+}
+
+// Close deletes the session created on the Rubrik cluster by the Client.
+func (c *Client) Close() error {
+	return c.Logout()
+}
@@ -0,0 +1,359 @@
+// Copyright 2018 Rubrik, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License prop
+//  http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rubrikcdm
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+)
+
+// SLADomain corresponds to the SLA Domain of POST /v2/sla_domain and GET, PUT /v2/sla_domain/{id}
+type SLADomain struct {
+	// ID is set by the Rubrik cluster and ignored by CreateSLA() and UpdateSLA()
+	ID          string         `json:"id,omitempty"`
+	Name        string         `json:"name"`
+	Frequencies SLAFrequencies `json:"frequencies"`
+	// AllowedBackupWindows restricts the time of the day snapshots are taken, any time is allowed when empty
+	AllowedBackupWindows []SLABackupWindow `json:"allowedBackupWindows"`
+	// FirstFullAllowedBackupWindows restricts the time of the first full snapshot of an object
+	FirstFullAllowedBackupWindows []SLABackupWindow `json:"firstFullAllowedBackupWindows"`
+	// LocalRetentionLimit is the number of seconds snapshots are kept on the Rubrik cluster before they are only
+	// available on the archive location. It is only used with ArchivalSpecs.
+	LocalRetentionLimit int                  `json:"localRetentionLimit,omitempty"`
+	ArchivalSpecs       []SLAArchivalSpec    `json:"archivalSpecs"`
+	ReplicationSpecs    []SLAReplicationSpec `json:"replicationSpecs"`
+	// ShowAdvancedUI enables the AdvancedUIConfig
+	ShowAdvancedUI   bool                  `json:"showAdvancedUi"`
+	AdvancedUIConfig []SLAAdvancedUIConfig `json:"advancedUiConfig,omitempty"`
+}
+
+// SLAFrequencies are the snapshot frequencies of an SLA Domain. A nil frequency is not used.
+type SLAFrequencies struct {
+	Hourly  *SLAFrequency `json:"hourly,omitempty"`
+	Daily   *SLAFrequency `json:"daily,omitempty"`
+	Weekly  *SLAFrequency `json:"weekly,omitempty"`
+	Monthly *SLAFrequency `json:"monthly,omitempty"`
+	Yearly  *SLAFrequency `json:"yearly,omitempty"`
+}
+
+// SLAFrequency is how often snapshots are taken and how long they are kept, ex. a Daily frequency of 1 with a Retention
+// of 30 takes a snapshot every day and keeps it for 30 days.
+type SLAFrequency struct {
+	// Frequency is the number of hours, days, weeks, months or years between two snapshots
+	Frequency int `json:"frequency"`
+	// Retention is the number of hours, days, weeks, months or years the snapshots are kept, unless the
+	// AdvancedUIConfig of the SLA Domain uses a different unit
+	Retention int `json:"retention"`
+	// DayOfWeek is the day of the week (ex. Saturday) of a Weekly frequency
+	DayOfWeek string `json:"dayOfWeek,omitempty"`
+	// DayOfMonth is the day of the month (FirstDay, Fifteenth or LastDay) of a Monthly frequency
+	DayOfMonth string `json:"dayOfMonth,omitempty"`
+	// DayOfYear is the day of the year (FirstDay or LastDay) of a Yearly frequency
+	DayOfYear string `json:"dayOfYear,omitempty"`
+	// YearStartMonth is the first month of the year (ex. January) of a Yearly frequency
+	YearStartMonth string `json:"yearStartMonth,omitempty"`
+}
+
+// SLABackupWindow is a period of time snapshots are allowed to start in.
+type SLABackupWindow struct {
+	StartTimeAttributes SLAStartTime `json:"startTimeAttributes"`
+	DurationInHours     int          `json:"durationInHours"`
+}
+
+// SLAStartTime is the start of an SLABackupWindow in the timezone of the Rubrik cluster.
+type SLAStartTime struct {
+	// DayOfWeek restricts the window to a day of the week, 1 for Sunday through 7 for Saturday. It is used every day when 0.
+	DayOfWeek int `json:"dayOfWeek,omitempty"`
+	Hour      int `json:"hour"`
+	Minute    int `json:"minute"`
+}
+
+// SLAArchivalSpec archives the snapshots of an SLA Domain to an archive location.
+type SLAArchivalSpec struct {
+	// LocationID is the ID of the archive location
+	LocationID string `json:"locationId"`
+	// ArchivalThreshold is the number of seconds after which a snapshot is archived
+	ArchivalThreshold int `json:"archivalThreshold"`
+}
+
+// SLAReplicationSpec replicates the snapshots of an SLA Domain to another Rubrik cluster.
+type SLAReplicationSpec struct {
+	// LocationID is the ID of the replication target
+	LocationID string `json:"locationId"`
+	// RetentionLimit is the number of seconds the snapshots are kept on the replication target
+	RetentionLimit int `json:"retentionLimit"`
+}
+
+// SLAAdvancedUIConfig sets the unit of the Retention of a frequency when ShowAdvancedUI is true, ex. a TimeUnit of Hourly
+// with a RetentionType of Daily keeps the hourly snapshots for a number of days.
+type SLAAdvancedUIConfig struct {
+	TimeUnit      string `json:"timeUnit"`
+	RetentionType string `json:"retentionType"`
+}
+
+// CreateSLA creates a new SLA Domain on the Rubrik cluster.
+//
+// The function will return one of the following:
+//
+//	An error matching ErrNoChangeRequired: No change required. The '{name}' SLA Domain is already configured on the Rubrik cluster.
+//
+//	The SLA Domain returned by POST /v2/sla_domain
+//
+// An error is returned when an SLA Domain with the same name but a different configuration already exists, use
+// UpdateSLA() to modify it.
+func (c *Credentials) CreateSLA(sla SLADomain, timeout ...int) (*SLADomain, error) {
+	return c.CreateSLAContext(context.Background(), sla, timeout...)
+}
+
+// CreateSLAContext is the same as CreateSLA with the addition of a context.Context that is used to cancel in-flight requests.
+func (c *Credentials) CreateSLAContext(ctx context.Context, sla SLADomain, timeout ...int) (*SLADomain, error) {
+
+	ctx, span := c.startSpan(ctx, "CreateSLA")
+	defer span.End()
+
+	httpTimeout := httpTimeout(timeout)
+
+	if err := validateSLADomain(sla); err != nil {
+		return nil, err
+	}
+
+	if err := c.requireCapability(ctx, CapabilitySLADomainManagement, httpTimeout); err != nil {
+		return nil, err
+	}
+
+	current, err := c.GetSLAContext(ctx, sla.Name, httpTimeout)
+	if err == nil {
+		if slaDomainsEqual(*current, sla) {
+			return nil, newError(ErrNoChangeRequired, "No change required. The '%s' SLA Domain is already configured on the Rubrik cluster", sla.Name)
+		}
+		return nil, fmt.Errorf("The Rubrik cluster already has an SLA Domain named '%s' with a different configuration. Use UpdateSLA() to modify it", sla.Name)
+	} else if errors.Is(err, ErrNotFound) == false {
+		return nil, err
+	}
+
+	sla.ID = ""
+	created, err := PostIntoContext[SLADomain](ctx, c, "v2", "/sla_domain", sla, httpTimeout)
+	if err != nil {
+		return nil, err
+	}
+
+	return &created, nil
+}
+
+// GetSLA returns the SLA Domain named "name".
+func (c *Credentials) GetSLA(name string, timeout ...int) (*SLADomain, error) {
+	return c.GetSLAContext(context.Background(), name, timeout...)
+}
+
+// GetSLAContext is the same as GetSLA with the addition of a context.Context that is used to cancel in-flight requests.
+func (c *Credentials) GetSLAContext(ctx context.Context, name string, timeout ...int) (*SLADomain, error) {
+
+	ctx, span := c.startSpan(ctx, "GetSLA")
+	defer span.End()
+
+	httpTimeout := httpTimeout(timeout)
+
+	if err := c.requireCapability(ctx, CapabilitySLADomainManagement, httpTimeout); err != nil {
+		return nil, err
+	}
+
+	slaID, err := c.ObjectIDContext(ctx, name, ObjectTypeSLA, httpTimeout)
+	if err != nil {
+		return nil, err
+	}
+
+	sla, err := GetIntoContext[SLADomain](ctx, c, "v2", fmt.Sprintf("/sla_domain/%s", slaID), httpTimeout)
+	if err != nil {
+		return nil, err
+	}
+
+	return &sla, nil
+}
+
+// ListSLAs returns every SLA Domain managed by the Rubrik cluster.
+func (c *Credentials) ListSLAs(timeout ...int) ([]SLADomain, error) {
+	return c.ListSLAsContext(context.Background(), timeout...)
+}
+
+// ListSLAsContext is the same as ListSLAs with the addition of a context.Context that is used to cancel in-flight requests.
+func (c *Credentials) ListSLAsContext(ctx context.Context, timeout ...int) ([]SLADomain, error) {
+
+	ctx, span := c.startSpan(ctx, "ListSLAs")
+	defer span.End()
+
+	httpTimeout := httpTimeout(timeout)
+
+	if err := c.requireCapability(ctx, CapabilitySLADomainManagement, httpTimeout); err != nil {
+		return nil, err
+	}
+
+	return ListAllContext[SLADomain](ctx, c, "v2", "/sla_domain?primary_cluster_id=local", httpTimeout)
+}
+
+// UpdateSLA replaces the configuration of the SLA Domain named "name" with "sla". The SLA Domain is renamed when the
+// Name of "sla" is different, an empty Name keeps the current name.
+//
+// The function will return one of the following:
+//
+//	An error matching ErrNoChangeRequired: No change required. The '{name}' SLA Domain is already configured with the provided configuration.
+//
+//	The SLA Domain returned by PUT /v2/sla_domain/{id}
+func (c *Credentials) UpdateSLA(name string, sla SLADomain, timeout ...int) (*SLADomain, error) {
+	return c.UpdateSLAContext(context.Background(), name, sla, timeout...)
+}
+
+// UpdateSLAContext is the same as UpdateSLA with the addition of a context.Context that is used to cancel in-flight requests.
+func (c *Credentials) UpdateSLAContext(ctx context.Context, name string, sla SLADomain, timeout ...int) (*SLADomain, error) {
+
+	ctx, span := c.startSpan(ctx, "UpdateSLA")
+	defer span.End()
+
+	httpTimeout := httpTimeout(timeout)
+
+	if sla.Name == "" {
+		sla.Name = name
+	}
+
+	if err := validateSLADomain(sla); err != nil {
+		return nil, err
+	}
+
+	current, err := c.GetSLAContext(ctx, name, httpTimeout)
+	if err != nil {
+		return nil, err
+	}
+
+	if slaDomainsEqual(*current, sla) {
+		return nil, newError(ErrNoChangeRequired, "No change required. The '%s' SLA Domain is already configured with the provided configuration", name)
+	}
+
+	sla.ID = current.ID
+	updated, err := PutIntoContext[SLADomain](ctx, c, "v2", fmt.Sprintf("/sla_domain/%s", current.ID), sla, httpTimeout)
+	if err != nil {
+		return nil, err
+	}
+
+	if sla.Name != name {
+		c.InvalidateObjectID(name, ObjectTypeSLA)
+	}
+
+	return &updated, nil
+}
+
+// DeleteSLA deletes the SLA Domain named "name". The Rubrik cluster rejects the deletion of an SLA Domain that still
+// protects objects.
+//
+// The function will return one of the following:
+//
+//	An error matching ErrNoChangeRequired: No change required. The Rubrik cluster does not contain an SLA Domain named '{name}'.
+//
+//	nil once DELETE /v2/sla_domain/{id} succeeded
+func (c *Credentials) DeleteSLA(name string, timeout ...int) error {
+	return c.DeleteSLAContext(context.Background(), name, timeout...)
+}
+
+// DeleteSLAContext is the same as DeleteSLA with the addition of a context.Context that is used to cancel in-flight requests.
+func (c *Credentials) DeleteSLAContext(ctx context.Context, name string, timeout ...int) error {
+
+	ctx, span := c.startSpan(ctx, "DeleteSLA")
+	defer span.End()
+
+	httpTimeout := httpTimeout(timeout)
+
+	if err := c.requireCapability(ctx, CapabilitySLADomainManagement, httpTimeout); err != nil {
+		return err
+	}
+
+	slaID, err := c.ObjectIDContext(ctx, name, ObjectTypeSLA, httpTimeout)
+	if errors.Is(err, ErrNotFound) {
+		return newError(ErrNoChangeRequired, "No change required. The Rubrik cluster does not contain an SLA Domain named '%s'", name)
+	} else if err != nil {
+		return err
+	}
+
+	if _, err := c.DeleteContext(ctx, "v2", fmt.Sprintf("/sla_domain/%s", slaID), httpTimeout); err != nil {
+		return err
+	}
+
+	c.InvalidateObjectID(name, ObjectTypeSLA)
+
+	return nil
+}
+
+// validateSLADomain returns an error when the SLA Domain is missing a required field.
+func validateSLADomain(sla SLADomain) error {
+
+	if sla.Name == "" {
+		return errors.New("The SLA Domain 'Name' must not be empty")
+	}
+
+	// Validated from the shortest to the longest frequency so the same error is always reported first
+	frequencies := []struct {
+		name      string
+		frequency *SLAFrequency
+	}{
+		{"Hourly", sla.Frequencies.Hourly},
+		{"Daily", sla.Frequencies.Daily},
+		{"Weekly", sla.Frequencies.Weekly},
+		{"Monthly", sla.Frequencies.Monthly},
+		{"Yearly", sla.Frequencies.Yearly},
+	}
+
+	configured := 0
+	for _, f := range frequencies {
+		if f.frequency == nil {
+			continue
+		}
+		if f.frequency.Frequency <= 0 || f.frequency.Retention <= 0 {
+			return fmt.Errorf("The %s 'Frequency' and 'Retention' of the SLA Domain must be greater than 0", f.name)
+		}
+		configured++
+	}
+
+	if configured == 0 {
+		return errors.New("The SLA Domain must have at least one frequency")
+	}
+
+	return nil
+}
+
+// slaDomainsEqual returns true when both SLA Domains have the same configuration, regardless of their ID.
+func slaDomainsEqual(a, b SLADomain) bool {
+	return reflect.DeepEqual(slaDomainSpec(a), slaDomainSpec(b))
+}
+
+// slaDomainSpec returns the configurable fields of an SLA Domain with empty lists instead of nil ones.
+func slaDomainSpec(sla SLADomain) SLADomain {
+
+	sla.ID = ""
+	if sla.AllowedBackupWindows == nil {
+		sla.AllowedBackupWindows = []SLABackupWindow{}
+	}
+	if sla.FirstFullAllowedBackupWindows == nil {
+		sla.FirstFullAllowedBackupWindows = []SLABackupWindow{}
+	}
+	if sla.ArchivalSpecs == nil {
+		sla.ArchivalSpecs = []SLAArchivalSpec{}
+	}
+	if sla.ReplicationSpecs == nil {
+		sla.ReplicationSpecs = []SLAReplicationSpec{}
+	}
+	if sla.AdvancedUIConfig == nil {
+		sla.AdvancedUIConfig = []SLAAdvancedUIConfig{}
+	}
+
+	return sla
+}
@@ -0,0 +1,286 @@
+// Copyright 2018 Rubrik, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License prop
+//  http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rubrikcdm
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"time"
+)
+
+// Snapshot is a snapshot of a Rubrik object returned by Snapshots().
+type Snapshot struct {
+	ID                     string    `json:"id"`
+	Date                   time.Time `json:"date"`
+	ExpirationDate         time.Time `json:"expirationDate"`
+	IsOnDemandSnapshot     bool      `json:"isOnDemandSnapshot"`
+	CloudState             int       `json:"cloudState"`
+	ConsistencyLevel       string    `json:"consistencyLevel"`
+	IndexState             int       `json:"indexState"`
+	ArchivalLocationIDs    []string  `json:"archivalLocationIds"`
+	ReplicationLocationIDs []string  `json:"replicationLocationIds"`
+	SLAID                  string    `json:"slaId"`
+	SLAName                string    `json:"slaName"`
+}
+
+// IsArchived returns true when a copy of the snapshot has been uploaded to at least one archival location.
+func (s Snapshot) IsArchived() bool {
+	return len(s.ArchivalLocationIDs) > 0
+}
+
+// IsReplicated returns true when a copy of the snapshot has been replicated to at least one Rubrik cluster.
+func (s Snapshot) IsReplicated() bool {
+	return len(s.ReplicationLocationIDs) > 0
+}
+
+// snapshotEndpoint is the Rubrik API endpoint that lists the snapshots of an ObjectType. When "field" is set, the
+// endpoint returns the object itself and its snapshots are embedded in that field.
+type snapshotEndpoint struct {
+	apiVersion string
+	endpoint   string
+	field      string
+}
+
+// snapshotEndpoints is the snapshotEndpoint of each ObjectType supported by Snapshots().
+var snapshotEndpoints = map[ObjectType]snapshotEndpoint{
+	ObjectTypeVMware:        {apiVersion: "v1", endpoint: "/vmware/vm/%s/snapshot"},
+	ObjectTypeAHV:           {apiVersion: "internal", endpoint: "/nutanix/vm/%s/snapshot"},
+	ObjectTypeHyperV:        {apiVersion: "internal", endpoint: "/hyperv/vm/%s/snapshot"},
+	ObjectTypeFileset:       {apiVersion: "v1", endpoint: "/fileset/%s", field: "snapshots"},
+	ObjectTypeManagedVolume: {apiVersion: "internal", endpoint: "/managed_volume/%s/snapshot"},
+	ObjectTypeMSSQLDB:       {apiVersion: "v1", endpoint: "/mssql/db/%s/snapshot"},
+	ObjectTypeOracleDB:      {apiVersion: "internal", endpoint: "/oracle/db/%s/snapshot"},
+	ObjectTypeEC2:           {apiVersion: "internal", endpoint: "/aws/ec2_instance/%s/snapshot"},
+	ObjectTypeVolumeGroup:   {apiVersion: "internal", endpoint: "/volume_group/%s/snapshot"},
+}
+
+// Snapshots returns every snapshot of the object, sorted from the oldest to the most recent. Use ObjectID() to look up
+// the "objectID" of an object from its name.
+//
+// Valid "objectType" choices are:
+//
+//	vmware, ahv, hyperv, fileset, managedVolume, mssqlDB, oracleDB, ec2, and volumeGroup
+func (c *Credentials) Snapshots(objectType ObjectType, objectID string, timeout ...int) ([]Snapshot, error) {
+	return c.SnapshotsContext(context.Background(), objectType, objectID, timeout...)
+}
+
+// SnapshotsContext is the same as Snapshots with the addition of a context.Context that is used to cancel in-flight requests.
+func (c *Credentials) SnapshotsContext(ctx context.Context, objectType ObjectType, objectID string, timeout ...int) ([]Snapshot, error) {
+
+	ctx, span := c.startSpan(ctx, "Snapshots")
+	defer span.End()
+
+	httpTimeout := httpTimeout(timeout)
+
+	objectType, err := checkObjectType("Snapshots", objectType)
+	if err != nil {
+		return nil, err
+	}
+
+	endpoint := snapshotEndpoints[objectType]
+	apiEndpoint := fmt.Sprintf(endpoint.endpoint, objectID)
+
+	var snapshots []Snapshot
+	if endpoint.field == "" {
+		snapshots, err = ListAllContext[Snapshot](ctx, c, endpoint.apiVersion, apiEndpoint, httpTimeout)
+	} else {
+		var detail map[string]json.RawMessage
+		detail, err = GetIntoContext[map[string]json.RawMessage](ctx, c, endpoint.apiVersion, apiEndpoint, httpTimeout)
+		if err == nil && detail[endpoint.field] != nil {
+			if err = json.Unmarshal(detail[endpoint.field], &snapshots); err != nil {
+				err = fmt.Errorf("Unable to decode the %s of GET %s into %T: %w", endpoint.field, apiEndpoint, snapshots, err)
+			}
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	sort.SliceStable(snapshots, func(i, j int) bool {
+		return snapshots[i].Date.Before(snapshots[j].Date)
+	})
+
+	return snapshots, nil
+}
+
+// SelectSnapshot returns the snapshot of the object chosen by the selector, ex:
+//
+//	snapshot, err := rubrik.SelectSnapshot(rubrikcdm.ObjectTypeVMware, vmID, rubrikcdm.LatestBefore(t).Where(rubrikcdm.Archived))
+//
+// The function will return an ErrNotFound error when none of the snapshots of the object match the selector.
+func (c *Credentials) SelectSnapshot(objectType ObjectType, objectID string, selector SnapshotSelector, timeout ...int) (Snapshot, error) {
+	return c.SelectSnapshotContext(context.Background(), objectType, objectID, selector, timeout...)
+}
+
+// SelectSnapshotContext is the same as SelectSnapshot with the addition of a context.Context that is used to cancel in-flight requests.
+func (c *Credentials) SelectSnapshotContext(ctx context.Context, objectType ObjectType, objectID string, selector SnapshotSelector, timeout ...int) (Snapshot, error) {
+
+	ctx, span := c.startSpan(ctx, "SelectSnapshot")
+	defer span.End()
+
+	snapshots, err := c.SnapshotsContext(ctx, objectType, objectID, timeout...)
+	if err != nil {
+		return Snapshot{}, err
+	}
+
+	snapshot, ok := selector.Select(snapshots)
+	if ok == false {
+		return Snapshot{}, newError(ErrNotFound, "The object '%s' does not have a snapshot matching '%s'", objectID, selector)
+	}
+
+	return snapshot, nil
+}
+
+// SnapshotFilter reports whether a snapshot should be considered by a SnapshotSelector.
+type SnapshotFilter func(snapshot Snapshot) bool
+
+var (
+	// OnDemand keeps the snapshots taken on demand instead of by an SLA Domain.
+	OnDemand SnapshotFilter = func(snapshot Snapshot) bool { return snapshot.IsOnDemandSnapshot }
+	// Archived keeps the snapshots that have been uploaded to an archival location.
+	Archived SnapshotFilter = Snapshot.IsArchived
+	// Replicated keeps the snapshots that have been replicated to another Rubrik cluster.
+	Replicated SnapshotFilter = Snapshot.IsReplicated
+)
+
+// TakenBetween keeps the snapshots taken at or after "start" and before "end".
+func TakenBetween(start, end time.Time) SnapshotFilter {
+	return func(snapshot Snapshot) bool {
+		return snapshot.Date.Before(start) == false && snapshot.Date.Before(end)
+	}
+}
+
+// Not keeps the snapshots that do not match the filter, ex. Not(OnDemand).
+func Not(filter SnapshotFilter) SnapshotFilter {
+	return func(snapshot Snapshot) bool { return filter(snapshot) == false }
+}
+
+// FilterSnapshots returns the snapshots that match every filter.
+func FilterSnapshots(snapshots []Snapshot, filters ...SnapshotFilter) []Snapshot {
+
+	matches := []Snapshot{}
+	for _, snapshot := range snapshots {
+		if matchesFilters(snapshot, filters) {
+			matches = append(matches, snapshot)
+		}
+	}
+	return matches
+}
+
+// matchesFilters returns true when the snapshot matches every filter.
+func matchesFilters(snapshot Snapshot, filters []SnapshotFilter) bool {
+	for _, filter := range filters {
+		if filter(snapshot) == false {
+			return false
+		}
+	}
+	return true
+}
+
+// SnapshotSelector chooses a single snapshot out of the snapshots of an object. Use Latest(), ClosestTo(), LatestBefore()
+// or OnOrAfter() to create one, and Where() to only consider the snapshots that match a set of filters.
+type SnapshotSelector struct {
+	description string
+	filters     []SnapshotFilter
+	// better returns true when "candidate" should be selected over "current"
+	better func(candidate, current Snapshot) bool
+	// accept returns false when the snapshot can not be selected at all
+	accept func(snapshot Snapshot) bool
+}
+
+// Latest selects the most recent snapshot.
+func Latest() SnapshotSelector {
+	return SnapshotSelector{
+		description: "latest",
+		better:      func(candidate, current Snapshot) bool { return candidate.Date.Before(current.Date) == false },
+	}
+}
+
+// ClosestTo selects the snapshot taken the closest to "t", before or after it.
+func ClosestTo(t time.Time) SnapshotSelector {
+	return SnapshotSelector{
+		description: fmt.Sprintf("closest to %s", t.Format(time.RFC3339)),
+		better: func(candidate, current Snapshot) bool {
+			return absDuration(candidate.Date.Sub(t)) < absDuration(current.Date.Sub(t))
+		},
+	}
+}
+
+// LatestBefore selects the most recent snapshot taken at or before "t".
+func LatestBefore(t time.Time) SnapshotSelector {
+	return SnapshotSelector{
+		description: fmt.Sprintf("latest before %s", t.Format(time.RFC3339)),
+		better:      func(candidate, current Snapshot) bool { return candidate.Date.Before(current.Date) == false },
+		accept:      func(snapshot Snapshot) bool { return snapshot.Date.After(t) == false },
+	}
+}
+
+// OnOrAfter selects the first snapshot taken at or after "t".
+func OnOrAfter(t time.Time) SnapshotSelector {
+	return SnapshotSelector{
+		description: fmt.Sprintf("on or after %s", t.Format(time.RFC3339)),
+		better:      func(candidate, current Snapshot) bool { return candidate.Date.Before(current.Date) },
+		accept:      func(snapshot Snapshot) bool { return snapshot.Date.Before(t) == false },
+	}
+}
+
+// Where returns a copy of the selector that only considers the snapshots matching every filter, ex.
+// Latest().Where(rubrikcdm.Archived).
+func (s SnapshotSelector) Where(filters ...SnapshotFilter) SnapshotSelector {
+	s.filters = append(append([]SnapshotFilter{}, s.filters...), filters...)
+	return s
+}
+
+// Select returns the snapshot chosen by the selector, or false when none of the snapshots match it.
+func (s SnapshotSelector) Select(snapshots []Snapshot) (Snapshot, bool) {
+
+	better := s.better
+	if better == nil {
+		better = Latest().better
+	}
+
+	var selected Snapshot
+	found := false
+	for _, snapshot := range snapshots {
+		if s.accept != nil && s.accept(snapshot) == false {
+			continue
+		}
+		if matchesFilters(snapshot, s.filters) == false {
+			continue
+		}
+		if found == false || better(snapshot, selected) {
+			selected = snapshot
+			found = true
+		}
+	}
+
+	return selected, found
+}
+
+// String describes the selector for error messages, ex. latest before 2019-04-09T17:56:00Z.
+func (s SnapshotSelector) String() string {
+	if s.description == "" {
+		return "latest"
+	}
+	return s.description
+}
+
+func absDuration(d time.Duration) time.Duration {
+	if d < 0 {
+		return -d
+	}
+	return d
+}
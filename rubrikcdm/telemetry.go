@@ -0,0 +1,180 @@
+// Copyright 2018 Rubrik, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License prop
+//  http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rubrikcdm
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// instrumentationName identifies the spans and metrics created by the SDK.
+const instrumentationName = "github.com/rubrikinc/rubrik-sdk-for-go/rubrikcdm"
+
+// objectIDPattern matches the path segments of an API endpoint that contain the ID of a Rubrik object.
+var objectIDPattern = regexp.MustCompile(`:::|^[0-9]+$|^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`)
+
+// telemetry holds the OpenTelemetry instruments used to trace and measure API calls.
+type telemetry struct {
+	tracer   trace.Tracer
+	requests metric.Int64Counter
+	errors   metric.Int64Counter
+	duration metric.Float64Histogram
+}
+
+var (
+	globalTelemetryOnce sync.Once
+	globalTelemetry     *telemetry
+)
+
+// WithTracerProvider creates the OpenTelemetry spans of the Client through the provided TracerProvider instead of the
+// global TracerProvider returned by otel.GetTracerProvider().
+func WithTracerProvider(tracerProvider trace.TracerProvider) ClientOption {
+	return func(config *clientConfig) error {
+		if tracerProvider == nil {
+			return errors.New("The 'tracerProvider' must not be nil")
+		}
+		config.tracerProvider = tracerProvider
+		return nil
+	}
+}
+
+// WithMeterProvider records the OpenTelemetry metrics of the Client through the provided MeterProvider instead of the
+// global MeterProvider returned by otel.GetMeterProvider().
+func WithMeterProvider(meterProvider metric.MeterProvider) ClientOption {
+	return func(config *clientConfig) error {
+		if meterProvider == nil {
+			return errors.New("The 'meterProvider' must not be nil")
+		}
+		config.meterProvider = meterProvider
+		return nil
+	}
+}
+
+// newTelemetry creates the tracer and metric instruments from the provided providers.
+func newTelemetry(tracerProvider trace.TracerProvider, meterProvider metric.MeterProvider) *telemetry {
+
+	meter := meterProvider.Meter(instrumentationName)
+
+	// Instrument creation only fails on an invalid name, in which case the returned no-op instrument is used
+	requests, _ := meter.Int64Counter("rubrik.api.requests", metric.WithDescription("Number of API calls sent to the Rubrik cluster"), metric.WithUnit("{request}"))
+	apiErrors, _ := meter.Int64Counter("rubrik.api.errors", metric.WithDescription("Number of API calls that failed or returned an error status code"), metric.WithUnit("{request}"))
+	duration, _ := meter.Float64Histogram("rubrik.api.duration", metric.WithDescription("Duration of the API calls sent to the Rubrik cluster, including retries"), metric.WithUnit("s"))
+
+	return &telemetry{
+		tracer:   tracerProvider.Tracer(instrumentationName),
+		requests: requests,
+		errors:   apiErrors,
+		duration: duration,
+	}
+}
+
+// telemetry returns the instruments of the Client. Credentials created through Connect() use the global OpenTelemetry providers.
+func (c *Credentials) telemetry() *telemetry {
+
+	if c.config != nil && c.config.telemetry != nil {
+		return c.config.telemetry
+	}
+
+	globalTelemetryOnce.Do(func() {
+		globalTelemetry = newTelemetry(otel.GetTracerProvider(), otel.GetMeterProvider())
+	})
+
+	return globalTelemetry
+}
+
+// startSpan starts the span of a helper function. The API calls sent by the helper are recorded as child spans.
+func (c *Credentials) startSpan(ctx context.Context, name string) (context.Context, trace.Span) {
+	return c.telemetry().tracer.Start(ctx, fmt.Sprintf("rubrikcdm.%s", name))
+}
+
+// tracedRequest sends the request through sendRequest() within a client span and records the API call metrics.
+func (c *Credentials) tracedRequest(ctx context.Context, method, requestURL string, requestBody []byte, timeout int) (*http.Response, []byte, error) {
+
+	telemetry := c.telemetry()
+	apiVersion, endpoint := endpointTemplate(requestURL)
+
+	attrs := []attribute.KeyValue{
+		attribute.String("http.request.method", method),
+		attribute.String("rubrik.api.version", apiVersion),
+		attribute.String("rubrik.api.endpoint", endpoint),
+	}
+
+	ctx, span := telemetry.tracer.Start(ctx, fmt.Sprintf("%s /%s%s", method, apiVersion, endpoint), trace.WithSpanKind(trace.SpanKindClient), trace.WithAttributes(attrs...))
+	defer span.End()
+
+	start := time.Now()
+	apiRequest, apiResponse, err := c.sendRequest(ctx, method, requestURL, requestBody, timeout)
+	duration := time.Since(start)
+
+	failed := err != nil
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	} else {
+		statusCode := attribute.Int("http.response.status_code", apiRequest.StatusCode)
+		span.SetAttributes(statusCode, attribute.String("server.address", apiRequest.Request.URL.Hostname()))
+		attrs = append(attrs, statusCode)
+		if apiRequest.StatusCode >= 400 {
+			failed = true
+			span.SetStatus(codes.Error, apiRequest.Status)
+		}
+	}
+
+	metricAttrs := metric.WithAttributes(attrs...)
+	telemetry.requests.Add(ctx, 1, metricAttrs)
+	telemetry.duration.Record(ctx, duration.Seconds(), metricAttrs)
+	if failed {
+		telemetry.errors.Add(ctx, 1, metricAttrs)
+	}
+
+	return apiRequest, apiResponse, err
+}
+
+// endpointTemplate returns the API version and the endpoint of the request URL with the object IDs replaced by {id} and
+// the query removed, ex. https://10.0.0.1/api/v1/vmware/vm/VirtualMachine:::123/snapshot?limit=1 returns v1 and
+// /vmware/vm/{id}/snapshot. The template keeps the cardinality of the span names and metric attributes low.
+func endpointTemplate(requestURL string) (string, string) {
+
+	parsedURL, err := url.Parse(requestURL)
+	if err != nil {
+		return "", ""
+	}
+
+	segments := strings.Split(strings.TrimPrefix(parsedURL.Path, "/api/"), "/")
+	if len(segments) == 0 {
+		return "", ""
+	}
+
+	apiVersion := segments[0]
+	for i, segment := range segments[1:] {
+		if objectIDPattern.MatchString(segment) {
+			segments[i+1] = "{id}"
+		}
+	}
+
+	return apiVersion, "/" + strings.Join(segments[1:], "/")
+}
@@ -0,0 +1,140 @@
+// Copyright 2018 Rubrik, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License prop
+//  http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rubrikcdm
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+// GetInto sends a GET request to the provided Rubrik API endpoint and decodes the API response into a value of type T
+// using encoding/json. A response that does not match T returns an error instead of causing a panic. The typed functions
+// accept Credentials, use the embedded Credentials of a Client (ex. rubrik.Credentials) to call them through a Client, ex:
+//
+//	clusterVersion, err := rubrikcdm.GetInto[rubrikcdm.ClusterVersion](rubrik, "v1", "/cluster/me/version")
+func GetInto[T any](c *Credentials, apiVersion, apiEndpoint string, timeout ...int) (T, error) {
+	return GetIntoContext[T](context.Background(), c, apiVersion, apiEndpoint, timeout...)
+}
+
+// GetIntoContext is the same as GetInto with the addition of a context.Context that is used to cancel in-flight requests.
+func GetIntoContext[T any](ctx context.Context, c *Credentials, apiVersion, apiEndpoint string, timeout ...int) (T, error) {
+	return decodeAPI[T](ctx, c, "GET", apiVersion, apiEndpoint, nil, httpTimeout(timeout))
+}
+
+// PostInto sends a POST request to the provided Rubrik API endpoint and decodes the API response into a value of type T.
+func PostInto[T any](c *Credentials, apiVersion, apiEndpoint string, config interface{}, timeout ...int) (T, error) {
+	return PostIntoContext[T](context.Background(), c, apiVersion, apiEndpoint, config, timeout...)
+}
+
+// PostIntoContext is the same as PostInto with the addition of a context.Context that is used to cancel in-flight requests.
+func PostIntoContext[T any](ctx context.Context, c *Credentials, apiVersion, apiEndpoint string, config interface{}, timeout ...int) (T, error) {
+	return decodeAPI[T](ctx, c, "POST", apiVersion, apiEndpoint, config, httpTimeout(timeout))
+}
+
+// PutInto sends a PUT request to the provided Rubrik API endpoint and decodes the API response into a value of type T.
+func PutInto[T any](c *Credentials, apiVersion, apiEndpoint string, config interface{}, timeout ...int) (T, error) {
+	return PutIntoContext[T](context.Background(), c, apiVersion, apiEndpoint, config, timeout...)
+}
+
+// PutIntoContext is the same as PutInto with the addition of a context.Context that is used to cancel in-flight requests.
+func PutIntoContext[T any](ctx context.Context, c *Credentials, apiVersion, apiEndpoint string, config interface{}, timeout ...int) (T, error) {
+	return decodeAPI[T](ctx, c, "PUT", apiVersion, apiEndpoint, config, httpTimeout(timeout))
+}
+
+// PatchInto sends a PATCH request to the provided Rubrik API endpoint and decodes the API response into a value of type T.
+func PatchInto[T any](c *Credentials, apiVersion, apiEndpoint string, config interface{}, timeout ...int) (T, error) {
+	return PatchIntoContext[T](context.Background(), c, apiVersion, apiEndpoint, config, timeout...)
+}
+
+// PatchIntoContext is the same as PatchInto with the addition of a context.Context that is used to cancel in-flight requests.
+func PatchIntoContext[T any](ctx context.Context, c *Credentials, apiVersion, apiEndpoint string, config interface{}, timeout ...int) (T, error) {
+	return decodeAPI[T](ctx, c, "PATCH", apiVersion, apiEndpoint, config, httpTimeout(timeout))
+}
+
+// DeleteInto sends a DELETE request to the provided Rubrik API endpoint and decodes the API response into a value of type T.
+// A 204 No Content response returns the zero value of T.
+func DeleteInto[T any](c *Credentials, apiVersion, apiEndpoint string, timeout ...int) (T, error) {
+	return DeleteIntoContext[T](context.Background(), c, apiVersion, apiEndpoint, timeout...)
+}
+
+// DeleteIntoContext is the same as DeleteInto with the addition of a context.Context that is used to cancel in-flight requests.
+func DeleteIntoContext[T any](ctx context.Context, c *Credentials, apiVersion, apiEndpoint string, timeout ...int) (T, error) {
+	return decodeAPI[T](ctx, c, "DELETE", apiVersion, apiEndpoint, nil, httpTimeout(timeout))
+}
+
+// JobStatusInto monitors the status of a specific Rubrik job in the same way as JobStatus and decodes the final API
+// response into a value of type T (ex. JobStatus).
+func JobStatusInto[T any](c *Credentials, jobStatusURL string, timeout ...int) (T, error) {
+	return JobStatusIntoContext[T](context.Background(), c, jobStatusURL, timeout...)
+}
+
+// JobStatusIntoContext is the same as JobStatusInto with the addition of a context.Context that is used to cancel in-flight requests and any polling.
+func JobStatusIntoContext[T any](ctx context.Context, c *Credentials, jobStatusURL string, timeout ...int) (T, error) {
+
+	job := c.NewJob(jobStatusURL, timeout...)
+
+	_, err := job.WaitContext(ctx)
+	if err != nil && errors.Is(err, ErrJobFailed) == false {
+		var result T
+		return result, err
+	}
+
+	job.mu.Lock()
+	apiRequest, apiResponse := job.apiRequest, job.apiResponse
+	job.mu.Unlock()
+
+	result, decodeErr := decodeInto[T](apiRequest, apiResponse)
+	if decodeErr != nil {
+		return result, decodeErr
+	}
+
+	return result, err
+}
+
+// decodeAPI sends the API call and decodes the API response into a value of type T.
+func decodeAPI[T any](ctx context.Context, c *Credentials, callType, apiVersion, apiEndpoint string, config interface{}, timeout int) (T, error) {
+
+	apiRequest, apiResponse, err := c.apiCall(ctx, callType, apiVersion, apiEndpoint, config, timeout)
+	if err != nil {
+		var result T
+		return result, err
+	}
+
+	return decodeInto[T](apiRequest, apiResponse)
+}
+
+// decodeInto returns an APIError when the Rubrik cluster responded with an error, otherwise it decodes the body of the
+// API response into a value of type T. An empty body (ex. 204 No Content) returns the zero value of T.
+func decodeInto[T any](apiRequest *http.Response, apiResponse []byte) (T, error) {
+
+	var result T
+
+	if _, err := parseAPIResponse(apiRequest, apiResponse); err != nil {
+		return result, err
+	}
+
+	if len(bytes.TrimSpace(apiResponse)) == 0 {
+		return result, nil
+	}
+
+	if err := json.Unmarshal(apiResponse, &result); err != nil {
+		return result, fmt.Errorf("Unable to decode the API response of %s %s into %T: %w", apiRequest.Request.Method, apiRequest.Request.URL.RequestURI(), result, err)
+	}
+
+	return result, nil
+}
@@ -0,0 +1,185 @@
+// Copyright 2018 Rubrik, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License prop
+//  http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rubrikcdm
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+)
+
+// ErrUnsupportedVersion is returned when an operation requires a more recent CDM version than the one running on the Rubrik cluster.
+var ErrUnsupportedVersion = errors.New("The operation is not supported by the CDM version of the Rubrik cluster")
+
+// cdmVersionPattern matches the CDM versions reported by GET /v1/cluster/me/version (ex. 5.3.2-p1-12345 or 9.0.1).
+var cdmVersionPattern = regexp.MustCompile(`^(\d+)\.(\d+)(?:\.(\d+))?(?:[-.](.+))?$`)
+
+// CDMVersion is a CDM version of a Rubrik cluster. The version 5.3.2-p1-12345 is parsed as Major 5, Minor 3, Patch 2
+// and Build "p1-12345".
+type CDMVersion struct {
+	Major int
+	Minor int
+	Patch int
+	// Build is the patch and build number following the release (ex. p1-12345). It is not used to compare versions.
+	Build string
+}
+
+// ParseCDMVersion parses a CDM version such as 5.3.2-p1-12345, 9.0.1 or 10.0. A missing patch number is parsed as 0.
+func ParseCDMVersion(version string) (CDMVersion, error) {
+
+	match := cdmVersionPattern.FindStringSubmatch(version)
+	if match == nil {
+		return CDMVersion{}, fmt.Errorf("'%s' is not a valid CDM version", version)
+	}
+
+	var parsed CDMVersion
+	parsed.Major, _ = strconv.Atoi(match[1])
+	parsed.Minor, _ = strconv.Atoi(match[2])
+	if match[3] != "" {
+		parsed.Patch, _ = strconv.Atoi(match[3])
+	}
+	parsed.Build = match[4]
+
+	return parsed, nil
+}
+
+// String returns the version in the Major.Minor.Patch format followed by the build when present (ex. 5.3.2-p1-12345).
+func (v CDMVersion) String() string {
+	if v.Build != "" {
+		return fmt.Sprintf("%d.%d.%d-%s", v.Major, v.Minor, v.Patch, v.Build)
+	}
+	return fmt.Sprintf("%d.%d.%d", v.Major, v.Minor, v.Patch)
+}
+
+// Compare returns -1, 0 or 1 when the version is respectively older, the same or more recent than the "other" version.
+// The build is ignored.
+func (v CDMVersion) Compare(other CDMVersion) int {
+	for _, diff := range []int{v.Major - other.Major, v.Minor - other.Minor, v.Patch - other.Patch} {
+		if diff < 0 {
+			return -1
+		} else if diff > 0 {
+			return 1
+		}
+	}
+	return 0
+}
+
+// AtLeast returns true when the version is the same or more recent than the "minimum" version.
+func (v CDMVersion) AtLeast(minimum CDMVersion) bool {
+	return v.Compare(minimum) >= 0
+}
+
+// Capability is an operation of the SDK that requires a minimum CDM version.
+type Capability string
+
+const (
+	// CapabilityAWSNativeAccount is the protection of AWS accounts through AddAWSNativeAccount().
+	CapabilityAWSNativeAccount Capability = "AWS native account"
+	// CapabilityEC2InstanceExport is the export of EC2 instance snapshots through ExportEC2Instance().
+	CapabilityEC2InstanceExport Capability = "EC2 instance export"
+	// CapabilitySLADomainManagement is the management of SLA Domains through CreateSLA(), UpdateSLA() and the other
+	// functions built on the v2 SLA Domain API.
+	CapabilitySLADomainManagement Capability = "SLA Domain management"
+	// CapabilityVMNetworkBinding is the NetworkBindings option of LiveMountVM(), InstantRecoverVM() and ExportVM().
+	CapabilityVMNetworkBinding Capability = "VM network binding"
+)
+
+// capabilities is the minimum CDM version of every Capability.
+var capabilities = map[Capability]CDMVersion{
+	CapabilityAWSNativeAccount:    {Major: 4, Minor: 2},
+	CapabilityEC2InstanceExport:   {Major: 4, Minor: 2},
+	CapabilitySLADomainManagement: {Major: 5, Minor: 0},
+	CapabilityVMNetworkBinding:    {Major: 5, Minor: 3},
+}
+
+// MinimumVersion returns the minimum CDM version required by the Capability. It returns false for an unknown Capability.
+func MinimumVersion(capability Capability) (CDMVersion, bool) {
+	version, ok := capabilities[capability]
+	return version, ok
+}
+
+// ClusterCDMVersion returns the parsed CDM version of the Rubrik cluster. A Client requests the version once and reuses
+// it for every subsequent call.
+func (c *Credentials) ClusterCDMVersion(timeout ...int) (CDMVersion, error) {
+	return c.ClusterCDMVersionContext(context.Background(), timeout...)
+}
+
+// ClusterCDMVersionContext is the same as ClusterCDMVersion with the addition of a context.Context that is used to cancel in-flight requests.
+func (c *Credentials) ClusterCDMVersionContext(ctx context.Context, timeout ...int) (CDMVersion, error) {
+
+	if c.config != nil {
+		c.config.versionMu.Lock()
+		defer c.config.versionMu.Unlock()
+
+		if c.config.version != nil {
+			return *c.config.version, nil
+		}
+	}
+
+	clusterVersion, err := c.ClusterVersionContext(ctx, timeout...)
+	if err != nil {
+		return CDMVersion{}, err
+	}
+
+	version, err := ParseCDMVersion(clusterVersion)
+	if err != nil {
+		return CDMVersion{}, err
+	}
+
+	if c.config != nil {
+		c.config.version = &version
+	}
+
+	return version, nil
+}
+
+// Supports returns true when the CDM version of the Rubrik cluster supports the Capability.
+func (c *Credentials) Supports(capability Capability, timeout ...int) (bool, error) {
+	return c.SupportsContext(context.Background(), capability, timeout...)
+}
+
+// SupportsContext is the same as Supports with the addition of a context.Context that is used to cancel in-flight requests.
+func (c *Credentials) SupportsContext(ctx context.Context, capability Capability, timeout ...int) (bool, error) {
+
+	err := c.requireCapability(ctx, capability, httpTimeout(timeout))
+	if errors.Is(err, ErrUnsupportedVersion) {
+		return false, nil
+	} else if err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+// requireCapability returns an error that matches ErrUnsupportedVersion when the Rubrik cluster does not support the Capability.
+func (c *Credentials) requireCapability(ctx context.Context, capability Capability, timeout int) error {
+
+	minimum, ok := capabilities[capability]
+	if ok == false {
+		return fmt.Errorf("'%s' is not a known Capability", capability)
+	}
+
+	version, err := c.ClusterCDMVersionContext(ctx, timeout)
+	if err != nil {
+		return err
+	}
+
+	if version.AtLeast(minimum) == false {
+		return newError(ErrUnsupportedVersion, "The Rubrik cluster must be running CDM version %s or later to use the %s capability (current version: %s)", minimum, capability, version)
+	}
+
+	return nil
+}
@@ -0,0 +1,159 @@
+// Copyright 2018 Rubrik, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License prop
+//  http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rubrikcdm
+
+import (
+	"context"
+	"errors"
+)
+
+// DefaultJobConcurrency is the number of job status requests sent at the same time when a concurrency is not provided to
+// WaitAll(), WaitAny() or WaitEach().
+const DefaultJobConcurrency = 10
+
+// JobResult is the outcome of a job monitored by WaitAll(), WaitAny() or WaitEach().
+type JobResult struct {
+	// Job is the monitored job
+	Job *Job
+	// Status is the last status of the job received from the Rubrik cluster
+	Status JobStatus
+	// Err is the error returned while polling the job, including ErrJobFailed when it did not succeed
+	Err error
+	// index is the position of the job in the slice provided to WaitEach()
+	index int
+}
+
+// WaitEach polls the status of every job concurrently and sends the result of each job on the returned channel as soon
+// as it finishes, in the order the jobs complete. At most "concurrency" status requests are sent at the same time, a
+// value of 0 uses the DefaultJobConcurrency. The channel is closed once every result has been sent.
+func WaitEach(jobs []*Job, concurrency int) <-chan JobResult {
+	return WaitEachContext(context.Background(), jobs, concurrency)
+}
+
+// WaitEachContext is the same as WaitEach with the addition of a context.Context that is used to cancel in-flight requests and any polling.
+// The jobs still running when the context is cancelled are sent with the context error.
+func WaitEachContext(ctx context.Context, jobs []*Job, concurrency int) <-chan JobResult {
+
+	if concurrency <= 0 {
+		concurrency = DefaultJobConcurrency
+	}
+
+	results := make(chan JobResult, len(jobs))
+	if len(jobs) == 0 {
+		close(results)
+		return results
+	}
+
+	// Limits the number of status requests in-flight, the jobs waiting for their next poll do not hold a slot
+	slots := make(chan struct{}, concurrency)
+	remaining := make(chan struct{}, len(jobs))
+
+	for i, job := range jobs {
+		go func(i int, job *Job) {
+			result := waitJob(ctx, job, slots)
+			result.index = i
+			results <- result
+			remaining <- struct{}{}
+		}(i, job)
+	}
+
+	go func() {
+		for range jobs {
+			<-remaining
+		}
+		close(results)
+	}()
+
+	return results
+}
+
+// waitJob polls the job according to the PollPolicy of its Credentials until it finishes, holding one of the slots
+// during each status request.
+func waitJob(ctx context.Context, job *Job, slots chan struct{}) JobResult {
+
+	if job == nil {
+		return JobResult{Err: errors.New("The job must not be nil")}
+	}
+
+	policy := job.credentials.pollPolicy()
+	interval := policy.InitialInterval
+
+	for {
+		select {
+		case slots <- struct{}{}:
+		case <-ctx.Done():
+			return JobResult{Job: job, Status: job.Status(), Err: ctx.Err()}
+		}
+
+		status, err := job.PollContext(ctx)
+		<-slots
+
+		if err != nil || jobDone(status.Status) {
+			return JobResult{Job: job, Status: status, Err: err}
+		}
+
+		if err := sleepContext(ctx, interval); err != nil {
+			return JobResult{Job: job, Status: status, Err: err}
+		}
+		interval = policy.next(interval)
+	}
+}
+
+// WaitAll polls the status of every job concurrently until they have all finished and returns their results in the same
+// order as "jobs", including a result at every position of a job provided more than once. At most "concurrency" status
+// requests are sent at the same time, a value of 0 uses the DefaultJobConcurrency. The returned error joins the error of
+// every job that did not succeed, use the Err field of each JobResult to find the jobs that failed.
+func WaitAll(jobs []*Job, concurrency int) ([]JobResult, error) {
+	return WaitAllContext(context.Background(), jobs, concurrency)
+}
+
+// WaitAllContext is the same as WaitAll with the addition of a context.Context that is used to cancel in-flight requests and any polling.
+func WaitAllContext(ctx context.Context, jobs []*Job, concurrency int) ([]JobResult, error) {
+
+	results := make([]JobResult, len(jobs))
+	for result := range WaitEachContext(ctx, jobs, concurrency) {
+		results[result.index] = result
+	}
+
+	var errs []error
+	for _, result := range results {
+		if result.Err != nil {
+			errs = append(errs, result.Err)
+		}
+	}
+
+	return results, errors.Join(errs...)
+}
+
+// WaitAny polls the status of every job concurrently and returns the result of the first job to finish, whether it
+// succeeded or not. The remaining jobs are no longer polled but keep running on the Rubrik cluster. At most
+// "concurrency" status requests are sent at the same time, a value of 0 uses the DefaultJobConcurrency.
+func WaitAny(jobs []*Job, concurrency int) (JobResult, error) {
+	return WaitAnyContext(context.Background(), jobs, concurrency)
+}
+
+// WaitAnyContext is the same as WaitAny with the addition of a context.Context that is used to cancel in-flight requests and any polling.
+func WaitAnyContext(ctx context.Context, jobs []*Job, concurrency int) (JobResult, error) {
+
+	if len(jobs) == 0 {
+		return JobResult{}, errors.New("At least one job must be provided")
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	result := <-WaitEachContext(ctx, jobs, concurrency)
+
+	return result, result.Err
+}